@@ -0,0 +1,302 @@
+/**
+ * apiv2.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
+	"github.com/MatrixLabsTech/flow-event-fetcher/server"
+)
+
+// ErrorV2 is an error of the v2 REST API, Code is the machine readable gRPC status name, e.g. INVALID_ARGUMENT
+type ErrorV2 struct {
+	Code string `json:"code"`
+
+	Message string `json:"message"`
+
+	// Field names the rejected request field of an INVALID_ARGUMENT error
+	Field string `json:"field,omitempty"`
+}
+
+// ResponseErrorV2 is the body of every v2 error response
+type ResponseErrorV2 struct {
+	Error ErrorV2 `json:"error"`
+}
+
+// errorCodeV2 is the name and the HTTP status of a gRPC code in the v2 REST API
+type errorCodeV2 struct {
+	name string
+
+	httpStatus int
+}
+
+var errorCodesV2 = map[codes.Code]errorCodeV2{
+	codes.InvalidArgument:   {"INVALID_ARGUMENT", http.StatusBadRequest},
+	codes.NotFound:          {"NOT_FOUND", http.StatusNotFound},
+	codes.Unauthenticated:   {"UNAUTHENTICATED", http.StatusUnauthorized},
+	codes.PermissionDenied:  {"PERMISSION_DENIED", http.StatusForbidden},
+	codes.ResourceExhausted: {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	codes.Canceled:          {"CANCELED", 499},
+	codes.Unimplemented:     {"UNIMPLEMENTED", http.StatusNotImplemented},
+	codes.Unavailable:       {"UNAVAILABLE", http.StatusServiceUnavailable},
+	codes.DeadlineExceeded:  {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+}
+
+// newErrorV2 classifies err like the gRPC API does, anything unknown is INTERNAL
+func newErrorV2(err error) (int, ResponseErrorV2) {
+	code, ok := errorCodesV2[server.StatusCode(err)]
+	if !ok {
+		code = errorCodeV2{"INTERNAL", http.StatusInternalServerError}
+	}
+	return code.httpStatus, ResponseErrorV2{Error: ErrorV2{
+		Code:    code.name,
+		Message: err.Error(),
+		Field:   server.InvalidField(err),
+	}}
+}
+
+func abortV2(c *gin.Context, err error) {
+	log.Error(err.Error())
+	httpStatus, body := newErrorV2(err)
+	c.AbortWithStatusJSON(httpStatus, body)
+}
+
+// bindQueryV2 reads and validates the QueryEventByBlockRange request in the body
+func bindQueryV2(c *gin.Context) (*pb.QueryEventByBlockRangeRequest, bool) {
+	var req pb.QueryEventByBlockRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortV2(c, &server.ValidationError{Field: "body", Message: err.Error()})
+		return nil, false
+	}
+	if err := server.ValidateQuery(&req); err != nil {
+		abortV2(c, err)
+		return nil, false
+	}
+	return &req, true
+}
+
+// registerV1Routes mounts the original routes, kept at the root and aliased under /v1
+func registerV1Routes(routes gin.IRoutes) {
+	routes.GET("/version", version)
+	routes.GET("/syncSpork", syncSpork)
+	routes.POST("/queryEventByBlockRange", queryEventByBlockRange)
+	routes.POST("/streamEventByBlockRange", streamEventByBlockRange)
+	routes.GET("/queryLatestBlockHeight", queryLatestBlockHeight)
+	routes.GET("/blockAtTime", blockAtTime)
+	routes.POST("/aggregateEvents", aggregateEvents)
+	routes.GET("/subscribe/sse", subscribeSSE)
+	routes.GET("/subscribe/ws", subscribeWebSocket)
+}
+
+// registerV2Routes mounts the v2 REST API, answering errors with ResponseErrorV2
+func registerV2Routes(group *gin.RouterGroup) {
+	group.GET("/version", version)
+	group.POST("/sporks/sync", syncSporkV2)
+	group.GET("/blocks/latest", latestBlockV2)
+	group.GET("/blocks/at-time", blockAtTimeV2)
+	group.POST("/events/query", queryEventsV2)
+	group.POST("/events/stream", streamEventsV2)
+	group.POST("/events/aggregate", aggregateEventsV2)
+}
+
+// syncSporkV2 sync spork
+// @Summary sync spork
+// @Description reloads the spork list from its source
+// @Tags flow-event-fetcher-v2
+// @Product application/json
+// @Success 200 {object} pb.SyncSporkResponse
+// @Failure 500 {object} ResponseErrorV2
+// @Router /v2/sporks/sync [post]
+func syncSporkV2(c *gin.Context) {
+	if err := flowClient.SyncSpork(); err != nil {
+		abortV2(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pb.SyncSporkResponse{Spork: flowClient.String()})
+}
+
+// latestBlockV2 query the latest block height
+// @Summary queries the latest sealed block height
+// @Description queries the latest sealed block height
+// @Tags flow-event-fetcher-v2
+// @Product application/json
+// @Success 200 {object} pb.QueryLatestBlockHeightResponse
+// @Failure 500 {object} ResponseErrorV2
+// @Router /v2/blocks/latest [get]
+func latestBlockV2(c *gin.Context) {
+	height, err := flowClient.QueryLatestBlockHeight(c.Request.Context())
+	if err != nil {
+		abortV2(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pb.QueryLatestBlockHeightResponse{LatestBlockHeight: height})
+}
+
+// blockAtTimeV2 query the first sealed block at or after a timestamp
+// @Summary queries the first sealed block at or after a timestamp
+// @Description looks up the block in the spork holding the timestamp, historical sporks included
+// @Tags flow-event-fetcher-v2
+// @Product application/json
+// @Param timestamp query string true "RFC 3339 timestamp, e.g. 2022-03-01T00:00:00Z"
+// @Success 200 {object} pb.QueryBlockAtTimeResponse
+// @Failure 400 {object} ResponseErrorV2
+// @Failure 404 {object} ResponseErrorV2
+// @Failure 500 {object} ResponseErrorV2
+// @Router /v2/blocks/at-time [get]
+func blockAtTimeV2(c *gin.Context) {
+	timestamp := c.Query("timestamp")
+	if timestamp == "" {
+		abortV2(c, &server.ValidationError{Field: "timestamp", Message: "timestamp is required"})
+		return
+	}
+	resp, err := server.BlockAtTime(c.Request.Context(), flowClient, &pb.QueryBlockAtTimeRequest{Timestamp: timestamp})
+	if err != nil {
+		abortV2(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// queryEventsV2 query event by block range, v2 events
+// @Summary queries event by block range, answering with v2 events
+// @Description same request as /queryEventByBlockRange, validated first: well-formed event types, start <= end.
+// @Description Every v2 event carries its block height, the hex block ID and the block timestamp with its full precision.
+// @Tags flow-event-fetcher-v2
+// @Accept  application/json
+// @Product application/json
+// @Param data body pb.QueryEventByBlockRangeRequest true "data"
+// @Success 200 {object} v2.QueryEventByBlockRangeResponse
+// @Failure 400 {object} ResponseErrorV2
+// @Failure 500 {object} ResponseErrorV2
+// @Router /v2/events/query [post]
+func queryEventsV2(c *gin.Context) {
+	req, ok := bindQueryV2(c)
+	if !ok {
+		return
+	}
+	log.Info(fmt.Sprintf("v2 query %v, from %d to %d", req.EventTypes(), req.Start, req.End))
+
+	resp, err := server.QueryEventsV2(c.Request.Context(), flowClient, req)
+	if err != nil {
+		abortV2(c, err)
+		return
+	}
+
+	log.Info(fmt.Sprintf("Got %d events", len(resp.Events)))
+	c.JSON(http.StatusOK, resp)
+}
+
+// aggregateEventsV2 count and sum events by group
+// @Summary counts events and sums up a numeric field over a block range
+// @Description same request as /aggregateEvents, the query validated like the one of /v2/events/query.
+// @Tags flow-event-fetcher-v2
+// @Accept  application/json
+// @Product application/json
+// @Param data body pb.AggregateEventsRequest true "data"
+// @Success 200 {object} pb.AggregateEventsResponse
+// @Failure 400 {object} ResponseErrorV2
+// @Failure 500 {object} ResponseErrorV2
+// @Router /v2/events/aggregate [post]
+func aggregateEventsV2(c *gin.Context) {
+	var req pb.AggregateEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortV2(c, &server.ValidationError{Field: "body", Message: err.Error()})
+		return
+	}
+	if _, err := server.ValidateAggregate(&req); err != nil {
+		abortV2(c, err)
+		return
+	}
+
+	resp, err := server.AggregateEvents(c.Request.Context(), flowClient, &req)
+	if err != nil {
+		abortV2(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// streamEventsV2 stream event by block range, v2 events
+// @Summary streams event by block range, answering with v2 events
+// @Description streams v2 events as newline delimited JSON, one line per fetched batch with a cursor to resume from.
+// @Description An error after the first line is sent as a last ResponseErrorV2 line.
+// @Tags flow-event-fetcher-v2
+// @Accept  application/json
+// @Produce application/x-ndjson
+// @Param data body pb.QueryEventByBlockRangeRequest true "data"
+// @Success 200 {object} v2.StreamEventsResponse
+// @Failure 400 {object} ResponseErrorV2
+// @Router /v2/events/stream [post]
+func streamEventsV2(c *gin.Context) {
+	req, ok := bindQueryV2(c)
+	if !ok {
+		return
+	}
+	start, end, ok, err := server.HeightRange(c.Request.Context(), flowClient, req)
+	if err != nil {
+		abortV2(c, err)
+		return
+	}
+	log.Info(fmt.Sprintf("v2 stream %v, from %d to %d", req.EventTypes(), start, end))
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	if !ok {
+		return
+	}
+	encoder := json.NewEncoder(c.Writer)
+	enricher := server.NewRequestEnricher(flowClient, req)
+
+	err = flowClient.StreamEventByBlockRange(c.Request.Context(), req.EventTypes(), start, end, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		// stop fetching once the client is gone
+		if err := c.Request.Context().Err(); err != nil {
+			return err
+		}
+		events, err := server.EventsToV2(c.Request.Context(), enricher, req, blockEvents)
+		if err != nil {
+			return err
+		}
+		err = encoder.Encode(pbv2.StreamEventsResponse{
+			Start:  start,
+			End:    end,
+			Cursor: end + 1,
+			Events: events,
+		})
+		if err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// the status line is already sent, report the error as the last line
+		log.Error(err.Error())
+		_, body := newErrorV2(err)
+		encoder.Encode(body)
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,113 @@
+/**
+ * backend.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// backendFlags are the flags selecting the FlowClient, shared by the service and the indexer
+type backendFlags struct {
+	stage            *string
+	sporkUrl         *string
+	alchemyEndpoint  *string
+	alchemyApiKey    *string
+	useAlchemy       *bool
+	maxQueryBlocks   *uint64
+	queryBatchSize   *uint64
+	queryConcurrency *int
+	balanceStrategy  *string
+	cachePath        *string
+	requestTimeout   *time.Duration
+	batchTimeout     *time.Duration
+	maxRetries       *int
+	retryBackoff     *time.Duration
+	maxRetryBackoff  *time.Duration
+}
+
+func registerBackendFlags(fs *flag.FlagSet) *backendFlags {
+	return &backendFlags{
+		stage:            fs.String("stage", "testnet", "network stage"),
+		sporkUrl:         fs.String("sporkUrl", "", "spork list source: http(s) url, local json file or \"embedded\", empty reads the flow sporks.json with the embedded snapshot as fallback"),
+		alchemyEndpoint:  fs.String("alchemyEndpoint", "", "alchemy endpoint"),
+		alchemyApiKey:    fs.String("alchemyApiKey", "", "alchemy api key"),
+		useAlchemy:       fs.Bool("useAlchemy", true, "use alchemy"),
+		maxQueryBlocks:   fs.Uint64("maxQueryBlocks", 2000, "max query blocks"),
+		queryBatchSize:   fs.Uint64("queryBatchSize", 200, "query batch size"),
+		queryConcurrency: fs.Int("queryConcurrency", 1, "number of batches fetched in parallel per query"),
+		balanceStrategy:  fs.String("balanceStrategy", spork.BalanceRoundRobin, "how batches are spread over the access nodes of a spork: roundrobin or latency"),
+		cachePath:        fs.String("cachePath", "", "path of the local event cache file, empty disables the cache"),
+		requestTimeout:   fs.Duration("requestTimeout", 0, "timeout of a whole query, 0 disables it"),
+		batchTimeout:     fs.Duration("batchTimeout", 0, "timeout of a single access node call, a timed out batch is retried in halves, 0 disables it"),
+		maxRetries:       fs.Int("maxRetries", spork.DefaultRetryPolicy.MaxRetries, "retries of a batch failing with a transient error (unavailable, deadline exceeded)"),
+		retryBackoff:     fs.Duration("retryBackoff", spork.DefaultRetryPolicy.InitialBackoff, "wait before the first retry of a batch, doubled on every further retry"),
+		maxRetryBackoff:  fs.Duration("maxRetryBackoff", spork.DefaultRetryPolicy.MaxBackoff, "max wait between two retries of a batch"),
+	}
+}
+
+func (backend *backendFlags) newFlowClient() spork.FlowClient {
+	var flowClient spork.FlowClient
+	timeouts := spork.Timeouts{Request: *backend.requestTimeout, Batch: *backend.batchTimeout}
+	retryPolicy := spork.DefaultRetryPolicy
+	retryPolicy.MaxRetries = *backend.maxRetries
+	retryPolicy.InitialBackoff = *backend.retryBackoff
+	retryPolicy.MaxBackoff = *backend.maxRetryBackoff
+
+	// the cache keeps the events of each network apart, an alchemy endpoint serves a single one
+	network := *backend.stage
+
+	// check if useAlchemy
+	if *backend.useAlchemy {
+		if *backend.alchemyEndpoint == "" {
+			log.Fatal("alchemy endpoint is required")
+		}
+		if *backend.alchemyApiKey == "" {
+			log.Fatal("alchemy api key is required")
+		}
+		network = *backend.alchemyEndpoint
+		sporkAlchemy := spork.NewSporkAlchemy(*backend.alchemyEndpoint, *backend.alchemyApiKey, *backend.maxQueryBlocks, *backend.queryBatchSize)
+		sporkAlchemy.SetQueryConcurrency(*backend.queryConcurrency)
+		sporkAlchemy.SetTimeouts(timeouts)
+		sporkAlchemy.SetRetryPolicy(retryPolicy)
+		flowClient = sporkAlchemy
+
+	} else {
+		sporkStore := spork.NewSporkStoreWithSource(*backend.stage, spork.NewSporkSource(*backend.sporkUrl), *backend.maxQueryBlocks, *backend.queryBatchSize)
+		sporkStore.SetQueryConcurrency(*backend.queryConcurrency)
+		sporkStore.SetTimeouts(timeouts)
+		sporkStore.SetRetryPolicy(retryPolicy)
+		if err := sporkStore.SetBalanceStrategy(*backend.balanceStrategy); err != nil {
+			log.Fatal(err)
+		}
+		flowClient = sporkStore
+	}
+
+	if *backend.cachePath != "" {
+		sporkCache, err := spork.NewSporkCache(flowClient, *backend.cachePath, network, *backend.maxQueryBlocks, *backend.queryBatchSize)
+		if err != nil {
+			log.Fatal(err)
+		}
+		flowClient = sporkCache
+	}
+	return flowClient
+}
@@ -23,9 +23,96 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/aggregateEvents": {
+            "post": {
+                "description": "counts the events selected by query, which takes the fields of /queryEventByBlockRange but limit, cursor and partial.\nfield names the numeric field (UFix64, Int, ...) whose sum, min and max are computed, groupBy a field to group by and bucket a block time bucket such as 1h.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "flow-event-fetcher"
+                ],
+                "summary": "counts events and sums up a numeric field over a block range",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AggregateEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AggregateEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/blockAtTime": {
+            "get": {
+                "description": "looks up the block in the spork holding the timestamp, historical sporks included",
+                "tags": [
+                    "flow-event-fetcher"
+                ],
+                "summary": "queries the first sealed block at or after a timestamp",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp, e.g. 2022-03-01T00:00:00Z",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryBlockAtTimeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
         "/queryEventByBlockRange": {
             "post": {
-                "description": "queries event by block range",
+                "description": "queries event by block range.\nWith partial set, the response is a pb.QueryEventByBlockRangeResponse listing the failed block ranges next to the fetched events.\nstartTime and endTime (RFC 3339) select the blocks of the window [startTime, endTime) instead of start and end.\nWith enrich set, every event carries its block ID, parent ID and the payer, proposer, authorizers and status of its transaction.\nWith limit or cursor set, the response is a pb.QueryEventByBlockRangeResponse holding one page of events and the nextCursor of the following page.\nfilter keeps the events whose fields match it (eq, in, gte, lte, prefix, combined with and / or), e.g. {\"field\": \"to\", \"in\": [\"0x1654653399040a61\"]}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -89,6 +176,133 @@ var doc = `{
                 }
             }
         },
+        "/streamEventByBlockRange": {
+            "post": {
+                "description": "streams event by block range as newline delimited JSON, one line per fetched batch.\nEach line carries the covered block range and a cursor to resume from after a disconnect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "flow-event-fetcher"
+                ],
+                "summary": "streams event by block range",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryEventByBlockRangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StreamEventsByBlockRangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscribe/sse": {
+            "get": {
+                "description": "follows the sealed head and sends an \"events\" message per fetched batch.\nPass start to resume from the cursor of the last received batch.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "flow-event-fetcher"
+                ],
+                "summary": "subscribes events with server-sent events",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "event types",
+                        "name": "event",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "start height, defaults to the current sealed head",
+                        "name": "start",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StreamEventsByBlockRangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscribe/ws": {
+            "get": {
+                "description": "follows the sealed head and sends a JSON message per fetched batch.\nPass start to resume from the cursor of the last received batch.",
+                "tags": [
+                    "flow-event-fetcher"
+                ],
+                "summary": "subscribes events with websocket",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "event types",
+                        "name": "event",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "start height, defaults to the current sealed head",
+                        "name": "start",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StreamEventsByBlockRangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
         "/syncSpork": {
             "get": {
                 "description": "sync spork",
@@ -109,7 +323,223 @@ var doc = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/main.ResponseError"
+                            "$ref": "#/definitions/main.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/blocks/at-time": {
+            "get": {
+                "description": "looks up the block in the spork holding the timestamp, historical sporks included",
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "queries the first sealed block at or after a timestamp",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp, e.g. 2022-03-01T00:00:00Z",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryBlockAtTimeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/blocks/latest": {
+            "get": {
+                "description": "queries the latest sealed block height",
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "queries the latest sealed block height",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryLatestBlockHeightResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/events/aggregate": {
+            "post": {
+                "description": "same request as /aggregateEvents, the query validated like the one of /v2/events/query.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "counts events and sums up a numeric field over a block range",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AggregateEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AggregateEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/events/query": {
+            "post": {
+                "description": "same request as /queryEventByBlockRange, validated first: well-formed event types, start \u003c= end.\nEvery v2 event carries its block height, the hex block ID and the block timestamp with its full precision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "queries event by block range, answering with v2 events",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryEventByBlockRangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.QueryEventByBlockRangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/events/stream": {
+            "post": {
+                "description": "streams v2 events as newline delimited JSON, one line per fetched batch with a cursor to resume from.\nAn error after the first line is sent as a last ResponseErrorV2 line.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "streams event by block range, answering with v2 events",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryEventByBlockRangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.StreamEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/sporks/sync": {
+            "post": {
+                "description": "reloads the spork list from its source",
+                "tags": [
+                    "flow-event-fetcher-v2"
+                ],
+                "summary": "sync spork",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SyncSporkResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ResponseErrorV2"
                         }
                     }
                 }
@@ -137,6 +567,21 @@ var doc = `{
         }
     },
     "definitions": {
+        "main.ErrorV2": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Field names the rejected request field of an INVALID_ARGUMENT error",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "main.ResponseError": {
             "type": "object",
             "properties": {
@@ -145,6 +590,14 @@ var doc = `{
                 }
             }
         },
+        "main.ResponseErrorV2": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/main.ErrorV2"
+                }
+            }
+        },
         "timestamppb.Timestamp": {
             "type": "object",
             "properties": {
@@ -158,23 +611,216 @@ var doc = `{
                 }
             }
         },
+        "v1.AggregateEventsRequest": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "description": "bucket groups the events by block time, a duration such as 1h or 15m",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "field is the numeric field, e.g. amount, summed with its min and max; empty only counts events",
+                    "type": "string"
+                },
+                "groupBy": {
+                    "description": "groupBy groups the events by the value of this field",
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/v1.QueryEventByBlockRangeRequest"
+                }
+            }
+        },
+        "v1.AggregateEventsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "description": "groups are ordered by bucket, then by key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.EventAggregate"
+                    }
+                }
+            }
+        },
+        "v1.BlockContext": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CadenceValue": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Types that are assignable to Value:\n\t*CadenceValue_Scalar\n\t*CadenceValue_Boolean\n\t*CadenceValue_Optional\n\t*CadenceValue_Array\n\t*CadenceValue_Dictionary\n\t*CadenceValue_Composite"
+                }
+            }
+        },
+        "v1.EventAggregate": {
+            "type": "object",
+            "properties": {
+                "bucketStart": {
+                    "description": "bucketStart is the start of the time bucket, only set with bucket",
+                    "$ref": "#/definitions/timestamppb.Timestamp"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "key is the groupBy value of the group, empty when the events are not grouped by field or lack it",
+                    "type": "string"
+                },
+                "max": {
+                    "type": "string"
+                },
+                "min": {
+                    "type": "string"
+                },
+                "sum": {
+                    "description": "sum, min and max are decimal strings over the events holding a number in field, empty without one",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.EventFilter": {
+            "type": "object",
+            "properties": {
+                "and": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.EventFilter"
+                    }
+                },
+                "eq": {
+                    "description": "eq compares numbers by value and addresses with or without 0x",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "field is the name of a top-level field of the event, e.g. to",
+                    "type": "string"
+                },
+                "gte": {
+                    "description": "gte and lte bound a numeric field, both inclusive",
+                    "type": "string"
+                },
+                "in": {
+                    "description": "in holds when the field equals one of the values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lte": {
+                    "type": "string"
+                },
+                "or": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.EventFilter"
+                    }
+                },
+                "prefix": {
+                    "description": "prefix matches the start of the field as text, addresses in lowercase hex with 0x",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.FailedHeightRange": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.QueryBlockAtTimeResponse": {
+            "type": "object",
+            "properties": {
+                "blockHeight": {
+                    "type": "integer"
+                },
+                "blockId": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "$ref": "#/definitions/timestamppb.Timestamp"
+                }
+            }
+        },
         "v1.QueryEventByBlockRangeRequest": {
             "type": "object",
             "properties": {
+                "cursor": {
+                    "description": "cursor is the nextCursor of the previous page, empty starts at start",
+                    "type": "string"
+                },
                 "end": {
                     "type": "integer"
                 },
+                "endTime": {
+                    "type": "string"
+                },
+                "enrich": {
+                    "description": "enrich attaches the block and transaction of every event, each fetched once per request",
+                    "type": "boolean"
+                },
                 "event": {
                     "type": "string"
                 },
+                "events": {
+                    "description": "events queries several event types at once, together with event if both are set",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "description": "filter only returns the events whose fields match it, evaluated before limit counts events",
+                    "$ref": "#/definitions/v1.EventFilter"
+                },
+                "includePayload": {
+                    "description": "includePayload attaches the raw JSON-CDC payload emitted by Flow to every event",
+                    "type": "boolean"
+                },
+                "limit": {
+                    "description": "limit pages QueryEventByBlockRange by event count, 0 returns every event of the range",
+                    "type": "integer"
+                },
+                "partial": {
+                    "description": "partial returns the events fetched so far instead of failing when some blocks cannot be fetched,\nthe missing ranges are listed in failedRanges. Only applies to QueryEventByBlockRange.",
+                    "type": "boolean"
+                },
                 "start": {
                     "type": "integer"
+                },
+                "startTime": {
+                    "description": "startTime and endTime select the blocks of the window [startTime, endTime) instead of start and end,\nboth are RFC 3339 timestamps, e.g. 2022-03-01T00:00:00Z",
+                    "type": "string"
                 }
             }
         },
         "v1.QueryEventByBlockRangeResponseEvent": {
             "type": "object",
             "properties": {
+                "block": {
+                    "description": "block and transaction are only set when enrich is requested",
+                    "$ref": "#/definitions/v1.BlockContext"
+                },
                 "blockId": {
                     "type": "integer"
                 },
@@ -184,9 +830,23 @@ var doc = `{
                 "index": {
                     "type": "integer"
                 },
+                "payload": {
+                    "description": "payload is the JSON-CDC encoded event as returned by the access node,\nonly set when includePayload is requested",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "payloadJson": {
+                    "description": "payloadJson is the same JSON-CDC document as a string, JSON clients get it escaped and decode it once more",
+                    "type": "string"
+                },
                 "timestamp": {
                     "$ref": "#/definitions/timestamppb.Timestamp"
                 },
+                "transaction": {
+                    "$ref": "#/definitions/v1.TransactionContext"
+                },
                 "transactionId": {
                     "type": "string"
                 },
@@ -210,6 +870,13 @@ var doc = `{
                 "name": {
                     "type": "string"
                 },
+                "type": {
+                    "description": "type is the declared Cadence type of the field",
+                    "type": "string"
+                },
+                "typedValue": {
+                    "$ref": "#/definitions/v1.CadenceValue"
+                },
                 "value": {
                     "type": "string"
                 }
@@ -223,6 +890,59 @@ var doc = `{
                 }
             }
         },
+        "v1.StreamEventsByBlockRangeResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.QueryEventByBlockRangeResponseEvent"
+                    }
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.SyncSporkResponse": {
+            "type": "object",
+            "properties": {
+                "spork": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TransactionContext": {
+            "type": "object",
+            "properties": {
+                "authorizers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errorMessage": {
+                    "description": "errorMessage is set when the transaction reverted",
+                    "type": "string"
+                },
+                "payer": {
+                    "type": "string"
+                },
+                "proposer": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status is the transaction status, e.g. SEALED",
+                    "type": "string"
+                }
+            }
+        },
         "v1.VersionResponse": {
             "type": "object",
             "properties": {
@@ -233,6 +953,103 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "v2.Event": {
+            "type": "object",
+            "properties": {
+                "block": {
+                    "description": "block and transaction are only set when enrich is requested",
+                    "$ref": "#/definitions/v1.BlockContext"
+                },
+                "blockHeight": {
+                    "type": "integer"
+                },
+                "blockId": {
+                    "description": "blockId is the hex encoded block ID",
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "payload is the JSON-CDC encoded event, only set when includePayload is requested",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "payloadJson": {
+                    "description": "payloadJson is the same JSON-CDC document as a string, JSON clients get it escaped and decode it once more",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "timestamp is the block timestamp with its full precision",
+                    "$ref": "#/definitions/timestamppb.Timestamp"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/v1.TransactionContext"
+                },
+                "transactionId": {
+                    "type": "string"
+                },
+                "transactionIndex": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.QueryEventByBlockRangeResponseValue"
+                    }
+                }
+            }
+        },
+        "v2.QueryEventByBlockRangeResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Event"
+                    }
+                },
+                "failedRanges": {
+                    "description": "failedRanges are the block ranges missing from events, only set in partial mode",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.FailedHeightRange"
+                    }
+                },
+                "nextCursor": {
+                    "description": "nextCursor fetches the next page when limit is set, empty once the range is done",
+                    "type": "string"
+                }
+            }
+        },
+        "v2.StreamEventsResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Event"
+                    }
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
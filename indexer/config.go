@@ -0,0 +1,86 @@
+/**
+ * indexer/config.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package indexer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EventConfig is an event type to index from StartHeight on
+type EventConfig struct {
+	Type        string `json:"type"`
+	StartHeight uint64 `json:"startHeight"`
+}
+
+// SinkConfig selects the database the events are written to
+type SinkConfig struct {
+	// Driver is either "sqlite" or "postgres"
+	Driver string `json:"driver"`
+	DSN    string `json:"dsn"`
+}
+
+type Config struct {
+	Events []EventConfig `json:"events"`
+
+	Sink SinkConfig `json:"sink"`
+
+	// BatchBlocks is the block range fetched and committed at once, it must not exceed maxQueryBlocks
+	BatchBlocks uint64 `json:"batchBlocks"`
+
+	// PollInterval is the wait between two catch ups once the head is reached, e.g. "5s"
+	PollInterval string `json:"pollInterval"`
+}
+
+// ReadConfig reads an indexer config from a json file
+func ReadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{BatchBlocks: 1000, PollInterval: "5s"}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (cfg *Config) Validate() error {
+	if len(cfg.Events) == 0 {
+		return errors.New("indexer: no events configured")
+	}
+	for _, event := range cfg.Events {
+		if event.Type == "" {
+			return errors.New("indexer: event type is required")
+		}
+	}
+	if cfg.BatchBlocks == 0 {
+		return errors.New("indexer: batchBlocks must be greater than 0")
+	}
+	if _, err := cfg.pollInterval(); err != nil {
+		return fmt.Errorf("indexer: invalid pollInterval: %w", err)
+	}
+	return nil
+}
+
+func (cfg *Config) pollInterval() (time.Duration, error) {
+	return time.ParseDuration(cfg.PollInterval)
+}
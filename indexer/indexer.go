@@ -0,0 +1,115 @@
+/**
+ * indexer/indexer.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package indexer
+
+import (
+	"context"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// Indexer backfills the configured event types from their start heights and then follows the
+// sealed head, writing every fetched batch to the sink together with its checkpoint.
+type Indexer struct {
+	flowClient spork.FlowClient
+
+	sink Sink
+
+	events []EventConfig
+
+	batchBlocks uint64
+
+	pollInterval time.Duration
+}
+
+func New(flowClient spork.FlowClient, sink Sink, cfg *Config) (*Indexer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	pollInterval, _ := cfg.pollInterval()
+	return &Indexer{
+		flowClient:   flowClient,
+		sink:         sink,
+		events:       cfg.Events,
+		batchBlocks:  cfg.BatchBlocks,
+		pollInterval: pollInterval,
+	}, nil
+}
+
+// Run indexes until ctx is done, errors are logged and retried on the next poll
+func (indexer *Indexer) Run(ctx context.Context) error {
+	for {
+		if err := indexer.CatchUp(ctx); err != nil {
+			log.Error("Indexer: ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(indexer.pollInterval):
+		}
+	}
+}
+
+// CatchUp indexes every event type up to the current sealed head
+func (indexer *Indexer) CatchUp(ctx context.Context) error {
+	head, err := indexer.flowClient.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range indexer.events {
+		if err := indexer.catchUpEvent(ctx, event, head); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (indexer *Indexer) catchUpEvent(ctx context.Context, event EventConfig, head uint64) error {
+	from := event.StartHeight
+	checkpoint, ok, err := indexer.sink.Checkpoint(event.Type)
+	if err != nil {
+		return err
+	}
+	if ok {
+		from = checkpoint + 1
+	}
+
+	for from <= head {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		end := from + indexer.batchBlocks - 1
+		if end > head {
+			end = head
+		}
+
+		log.Info("Indexer: index ", event.Type, " ", from, " - ", end)
+		err := indexer.flowClient.StreamEventByBlockRange(ctx, []string{event.Type}, from, end, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+			return indexer.sink.Write(event.Type, spork.BlockEventsToJSON(blockEvents), end)
+		})
+		if err != nil {
+			return err
+		}
+		from = end + 1
+	}
+	return nil
+}
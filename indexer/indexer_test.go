@@ -0,0 +1,150 @@
+package indexer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+const testEventSignature = "A.1654653399040a61.FlowToken.TokensDeposited"
+
+// fakeFlowClient emits one deposit every tenth block up to head
+type fakeFlowClient struct {
+	head uint64
+
+	fetched int
+}
+
+func (f *fakeFlowClient) String() string {
+	return "fakeFlowClient"
+}
+
+func (f *fakeFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	eventType := &cadence.EventType{
+		QualifiedIdentifier: "FlowToken.TokensDeposited",
+		Fields:              []cadence.Field{{Identifier: "amount", Type: cadence.UFix64Type{}}},
+	}
+	amount, _ := cadence.NewUFix64("1.5")
+
+	result := make([]client.BlockEvents, 0)
+	for height := start; height <= end; height++ {
+		blockEvent := client.BlockEvents{Height: height, BlockTimestamp: time.Unix(int64(height), 0)}
+		if height%10 == 0 {
+			blockEvent.Events = []flow.Event{{
+				Type:          eventTypes[0],
+				TransactionID: flow.BytesToID([]byte{byte(height), byte(height >> 8)}),
+				Value:         cadence.NewEvent([]cadence.Value{amount}).WithType(eventType),
+			}}
+		}
+		result = append(result, blockEvent)
+	}
+	return result, nil
+}
+
+func (f *fakeFlowClient) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler spork.BlockEventsHandler) error {
+	f.fetched += int(end - start + 1)
+	ret, _ := f.QueryEventByBlockRange(ctx, eventTypes, start, end)
+	return handler(start, end, ret)
+}
+
+func (f *fakeFlowClient) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
+	return f.head, nil
+}
+
+func (f *fakeFlowClient) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	return uint64(timestamp.Unix()), nil
+}
+
+func (f *fakeFlowClient) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	return &flow.BlockHeader{Height: height, Timestamp: time.Unix(int64(height), 0)}, nil
+}
+
+func (f *fakeFlowClient) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*spork.TransactionInfo, error) {
+	return &spork.TransactionInfo{Status: flow.TransactionStatusSealed}, nil
+}
+
+func (f *fakeFlowClient) SyncSpork() error {
+	return nil
+}
+
+func (f *fakeFlowClient) Close() error {
+	return nil
+}
+
+func countEvents(t *testing.T, sink *SQLSink) int {
+	var count int
+	require.Nil(t, sink.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&count))
+	return count
+}
+
+func TestIndexerBackfillAndResume(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "events.db")
+	cfg := &Config{
+		Events:       []EventConfig{{Type: testEventSignature, StartHeight: 100}},
+		Sink:         SinkConfig{Driver: "sqlite", DSN: dsn},
+		BatchBlocks:  30,
+		PollInterval: "1s",
+	}
+
+	sink, err := NewSQLSink(cfg.Sink.Driver, cfg.Sink.DSN)
+	require.Nil(t, err)
+	flowClient := &fakeFlowClient{head: 199}
+	idx, err := New(flowClient, sink, cfg)
+	require.Nil(t, err)
+
+	require.Nil(t, idx.CatchUp(context.Background()))
+	require.Equal(t, 100, flowClient.fetched)
+	require.Equal(t, 10, countEvents(t, sink))
+	checkpoint, ok, err := sink.Checkpoint(testEventSignature)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(199), checkpoint)
+	require.Nil(t, sink.Close())
+
+	// a restarted indexer resumes from the checkpoint
+	sink, err = NewSQLSink(cfg.Sink.Driver, cfg.Sink.DSN)
+	require.Nil(t, err)
+	defer sink.Close()
+	flowClient = &fakeFlowClient{head: 250}
+	idx, err = New(flowClient, sink, cfg)
+	require.Nil(t, err)
+
+	require.Nil(t, idx.CatchUp(context.Background()))
+	require.Equal(t, 51, flowClient.fetched)
+	require.Equal(t, 16, countEvents(t, sink))
+	checkpoint, _, err = sink.Checkpoint(testEventSignature)
+	require.Nil(t, err)
+	require.Equal(t, uint64(250), checkpoint)
+}
+
+func TestSQLSinkWriteIsIdempotent(t *testing.T) {
+	sink, err := NewSQLSink("sqlite", "file:"+filepath.Join(t.TempDir(), "events.db"))
+	require.Nil(t, err)
+	defer sink.Close()
+
+	ret, _ := (&fakeFlowClient{}).QueryEventByBlockRange(context.Background(), []string{testEventSignature}, 10, 30)
+	events := spork.BlockEventsToJSON(ret)
+	require.Nil(t, sink.Write(testEventSignature, events, 30))
+	require.Nil(t, sink.Write(testEventSignature, events, 30))
+	require.Equal(t, 3, countEvents(t, sink))
+
+	_, ok, err := sink.Checkpoint("A.0x1.Other.Event")
+	require.Nil(t, err)
+	require.False(t, ok)
+}
+
+func TestSQLSinkRebind(t *testing.T) {
+	sink := &SQLSink{driver: "postgres"}
+	require.Equal(t, "INSERT INTO checkpoints VALUES ($1, $2)", sink.rebind("INSERT INTO checkpoints VALUES (?, ?)"))
+
+	sink = &SQLSink{driver: "sqlite"}
+	require.Equal(t, "SELECT ?", sink.rebind("SELECT ?"))
+}
@@ -0,0 +1,166 @@
+/**
+ * indexer/sink.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package indexer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// Sink stores indexed events together with a checkpoint per event type
+type Sink interface {
+	// Checkpoint returns the last indexed height of eventType, ok is false if nothing was indexed yet
+	Checkpoint(eventType string) (height uint64, ok bool, err error)
+
+	// Write stores the events and moves the checkpoint of eventType to height atomically
+	Write(eventType string, events []*pb.QueryEventByBlockRangeResponseEvent, height uint64) error
+
+	Close() error
+}
+
+// SQLSink is a Sink writing to a SQLite or Postgres database
+type SQLSink struct {
+	db *sql.DB
+
+	driver string
+}
+
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS events (
+		event_id TEXT PRIMARY KEY,
+		block_height BIGINT NOT NULL,
+		block_timestamp TIMESTAMP NOT NULL,
+		type TEXT NOT NULL,
+		transaction_id TEXT NOT NULL,
+		transaction_index BIGINT NOT NULL,
+		event_index BIGINT NOT NULL,
+		event_values TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS events_type_height ON events (type, block_height)`,
+	`CREATE TABLE IF NOT EXISTS checkpoints (
+		event_type TEXT PRIMARY KEY,
+		height BIGINT NOT NULL
+	)`,
+}
+
+// NewSQLSink opens the database and creates the tables, driver is either "sqlite" or "postgres"
+func NewSQLSink(driver string, dsn string) (*SQLSink, error) {
+	if driver != "sqlite" && driver != "postgres" {
+		return nil, fmt.Errorf("indexer: unsupported sink driver %s", driver)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == "sqlite" {
+		// sqlite allows a single writer
+		db.SetMaxOpenConns(1)
+	}
+	for _, statement := range sqlSchema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &SQLSink{db: db, driver: driver}, nil
+}
+
+// rebind turns the ? placeholders into the $n placeholders of postgres
+func (sink *SQLSink) rebind(query string) string {
+	if sink.driver != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (sink *SQLSink) Checkpoint(eventType string) (uint64, bool, error) {
+	var height uint64
+	err := sink.db.QueryRow(sink.rebind(`SELECT height FROM checkpoints WHERE event_type = ?`), eventType).Scan(&height)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+func (sink *SQLSink) Write(eventType string, events []*pb.QueryEventByBlockRangeResponseEvent, height uint64) error {
+	tx, err := sink.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertEvent, err := tx.Prepare(sink.rebind(`INSERT INTO events
+		(event_id, block_height, block_timestamp, type, transaction_id, transaction_index, event_index, event_values)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (event_id) DO NOTHING`))
+	if err != nil {
+		return err
+	}
+	defer insertEvent.Close()
+
+	for _, event := range events {
+		values, err := json.Marshal(event.Values)
+		if err != nil {
+			return err
+		}
+		_, err = insertEvent.Exec(
+			event.EventID,
+			event.BlockId,
+			event.Timestamp.AsTime().UTC().Format(time.RFC3339Nano),
+			event.Type,
+			event.TransactionId,
+			event.TransactionIndex,
+			event.Index,
+			string(values))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(sink.rebind(`INSERT INTO checkpoints (event_type, height) VALUES (?, ?)
+		ON CONFLICT (event_type) DO UPDATE SET height = excluded.height`), eventType, height)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (sink *SQLSink) Close() error {
+	return sink.db.Close()
+}
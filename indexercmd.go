@@ -0,0 +1,69 @@
+/**
+ * indexercmd.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MatrixLabsTech/flow-event-fetcher/indexer"
+)
+
+// runIndexer runs the indexer subcommand: flow-event-fetcher indexer -config indexer.json
+func runIndexer(args []string) {
+	fs := flag.NewFlagSet("indexer", flag.ExitOnError)
+	configPath := fs.String("config", "indexer.json", "indexer config file")
+	backend := registerBackendFlags(fs)
+	fs.Parse(args)
+
+	cfg, err := indexer.ReadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.BatchBlocks > *backend.maxQueryBlocks {
+		log.Fatal(fmt.Sprintf("batchBlocks %d is greater than maxQueryBlocks %d", cfg.BatchBlocks, *backend.maxQueryBlocks))
+	}
+
+	sink, err := indexer.NewSQLSink(cfg.Sink.Driver, cfg.Sink.DSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sink.Close()
+
+	flowClient := backend.newFlowClient()
+	defer flowClient.Close()
+	log.Info(fmt.Sprintf("indexer backend: %s", flowClient.String()))
+
+	idx, err := indexer.New(flowClient, sink, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("Starting indexer...")
+	idx.Run(ctx)
+	log.Info("Indexer stopped")
+}
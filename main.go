@@ -20,19 +20,26 @@ package main
 //go:generate swag init  --parseDependency --parseDepth=2 --parseVendor
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/onflow/flow-go-sdk/client"
 	log "github.com/sirupsen/logrus"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
+	"google.golang.org/grpc/codes"
 
 	_ "github.com/MatrixLabsTech/flow-event-fetcher/docs"
 	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	"github.com/MatrixLabsTech/flow-event-fetcher/server"
 	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
 )
 
@@ -54,7 +61,7 @@ type ResponseError struct {
 // @Router /version [get]
 func version(c *gin.Context) {
 	c.JSON(http.StatusOK, pb.VersionResponse{
-		Version:     "1.0.0",
+		Version:     server.Version,
 		BackendMode: backendMode,
 	})
 }
@@ -88,7 +95,7 @@ func syncSpork(c *gin.Context) {
 // @Failure 500 {object} ResponseError
 // @Router /queryLatestBlockHeight [get]
 func queryLatestBlockHeight(c *gin.Context) {
-	height, err := flowClient.QueryLatestBlockHeight()
+	height, err := flowClient.QueryLatestBlockHeight(c.Request.Context())
 	if err != nil {
 		log.Error(err.Error())
 		c.JSON(http.StatusInternalServerError, ResponseError{Error: err.Error()})
@@ -97,9 +104,88 @@ func queryLatestBlockHeight(c *gin.Context) {
 	c.JSON(http.StatusOK, pb.QueryLatestBlockHeightResponse{LatestBlockHeight: height})
 }
 
+type BlockAtTimeDto struct {
+	Timestamp string `form:"timestamp" binding:"required"`
+}
+
+// blockAtTime query the first sealed block at or after a timestamp
+// @Summary queries the first sealed block at or after a timestamp
+// @Description looks up the block in the spork holding the timestamp, historical sporks included
+// @Tags flow-event-fetcher
+// @Product application/json
+// @Param timestamp query string true "RFC 3339 timestamp, e.g. 2022-03-01T00:00:00Z"
+// @Success 200 {object} pb.QueryBlockAtTimeResponse
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /blockAtTime [get]
+func blockAtTime(c *gin.Context) {
+	var blockAtTimeDto BlockAtTimeDto
+	err := c.ShouldBindQuery(&blockAtTimeDto)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return
+	}
+
+	resp, err := server.BlockAtTime(c.Request.Context(), flowClient, &pb.QueryBlockAtTimeRequest{Timestamp: blockAtTimeDto.Timestamp})
+	if err != nil {
+		log.Error(err.Error())
+		switch {
+		case errors.Is(err, server.ErrInvalidTimestamp):
+			c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		case errors.Is(err, server.ErrBlockNotSealed):
+			c.JSON(http.StatusNotFound, ResponseError{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ResponseError{Error: err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// aggregateEvents count and sum events by group
+// @Summary counts events and sums up a numeric field over a block range
+// @Description counts the events selected by query, which takes the fields of /queryEventByBlockRange but limit, cursor and partial.
+// @Description field names the numeric field (UFix64, Int, ...) whose sum, min and max are computed, groupBy a field to group by and bucket a block time bucket such as 1h.
+// @Tags flow-event-fetcher
+// @Accept  application/json
+// @Product application/json
+// @Param data body pb.AggregateEventsRequest true "data"
+// @Success 200 {object} pb.AggregateEventsResponse
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /aggregateEvents [post]
+func aggregateEvents(c *gin.Context) {
+	var aggregateEventsDto pb.AggregateEventsRequest
+	err := c.ShouldBindJSON(&aggregateEventsDto)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return
+	}
+
+	resp, err := server.AggregateEvents(c.Request.Context(), flowClient, &aggregateEventsDto)
+	if err != nil {
+		log.Error(err.Error())
+		if server.StatusCode(err) == codes.InvalidArgument {
+			c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, ResponseError{Error: err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // queryEventByBlockRange query event by block range
 // @Summary queries event by block range
-// @Description queries event by block range
+// @Description queries event by block range.
+// @Description With partial set, the response is a pb.QueryEventByBlockRangeResponse listing the failed block ranges next to the fetched events.
+// @Description startTime and endTime (RFC 3339) select the blocks of the window [startTime, endTime) instead of start and end.
+// @Description With enrich set, every event carries its block ID, parent ID and the payer, proposer, authorizers and status of its transaction.
+// @Description With limit or cursor set, the response is a pb.QueryEventByBlockRangeResponse holding one page of events and the nextCursor of the following page.
+// @Description filter keeps the events whose fields match it (eq, in, gte, lte, prefix, combined with and / or), e.g. {"field": "to", "in": ["0x1654653399040a61"]}.
 // @Tags flow-event-fetcher
 // @Accept  application/json
 // @Product application/json
@@ -116,67 +202,156 @@ func queryEventByBlockRange(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
 		return
 	}
-	log.Info(fmt.Sprintf("query %s, from %d to %d",
-		queryEventByBlockRangeDto.Event,
+	eventTypes := queryEventByBlockRangeDto.EventTypes()
+	if len(eventTypes) == 0 {
+		c.JSON(http.StatusBadRequest, ResponseError{Error: "at least one event type is required"})
+		return
+	}
+	log.Info(fmt.Sprintf("query %v, from %d to %d",
+		eventTypes,
 		queryEventByBlockRangeDto.Start,
 		queryEventByBlockRangeDto.End))
 
-	ret, err := flowClient.QueryEventByBlockRange(
-		queryEventByBlockRangeDto.Event,
-		queryEventByBlockRangeDto.Start,
-		queryEventByBlockRangeDto.End)
+	resp, err := server.QueryEvents(c.Request.Context(), flowClient, &queryEventByBlockRangeDto)
 	if err != nil {
 		log.Error(err.Error())
 		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
 		return
 	}
 
-	jsonRet := spork.BlockEventsToJSON(ret)
-	log.Info(fmt.Sprintf("Got %d events", len(jsonRet)))
-	c.JSON(http.StatusOK, jsonRet)
+	log.Info(fmt.Sprintf("Got %d events", len(resp.Events)))
+	if queryEventByBlockRangeDto.Partial || server.IsPaged(&queryEventByBlockRangeDto) {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp.Events)
 
 }
 
+// streamEventByBlockRange stream event by block range
+// @Summary streams event by block range
+// @Description streams event by block range as newline delimited JSON, one line per fetched batch.
+// @Description Each line carries the covered block range and a cursor to resume from after a disconnect.
+// @Tags flow-event-fetcher
+// @Accept  application/json
+// @Produce application/x-ndjson
+// @Param data body pb.QueryEventByBlockRangeRequest true "data"
+// @Success 200 {object} pb.StreamEventsByBlockRangeResponse
+// @Failure 400 {object} ResponseError
+// @Router /streamEventByBlockRange [post]
+func streamEventByBlockRange(c *gin.Context) {
+	var queryEventByBlockRangeDto pb.QueryEventByBlockRangeRequest
+	err := c.Bind(&queryEventByBlockRangeDto)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return
+	}
+	eventTypes := queryEventByBlockRangeDto.EventTypes()
+	if len(eventTypes) == 0 {
+		c.JSON(http.StatusBadRequest, ResponseError{Error: "at least one event type is required"})
+		return
+	}
+	if _, err := spork.NewEventFilter(queryEventByBlockRangeDto.Filter); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return
+	}
+	start, end, ok, err := server.HeightRange(c.Request.Context(), flowClient, &queryEventByBlockRangeDto)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return
+	}
+	log.Info(fmt.Sprintf("stream %v, from %d to %d", eventTypes, start, end))
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	if !ok {
+		return
+	}
+	encoder := json.NewEncoder(c.Writer)
+	enricher := server.NewRequestEnricher(flowClient, &queryEventByBlockRangeDto)
+
+	err = flowClient.StreamEventByBlockRange(
+		c.Request.Context(),
+		eventTypes,
+		start,
+		end,
+		func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+			// stop fetching once the client is gone
+			if err := c.Request.Context().Err(); err != nil {
+				return err
+			}
+			events, err := server.EventsToJSON(c.Request.Context(), enricher, &queryEventByBlockRangeDto, blockEvents)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(pb.StreamEventsByBlockRangeResponse{
+				Start:  start,
+				End:    end,
+				Cursor: end + 1,
+				Events: events,
+			})
+			if err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	if err != nil {
+		// the status line is already sent, report the error as the last line
+		log.Error(err.Error())
+		encoder.Encode(ResponseError{Error: err.Error()})
+		c.Writer.Flush()
+	}
+}
+
 // @title flow-event-fetcher API
 // @version 1.0.1
 // @description flow-event-fetcher interface documentation
 // @host localhost:8989
 // @BasePath
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "indexer" {
+		runIndexer(os.Args[2:])
+		return
+	}
+
 	port := flag.String("port", "8989", "port to listen on")
-	stage := flag.String("stage", "testnet", "network stage")
-	alchemyEndpoint := flag.String("alchemyEndpoint", "", "alchemy endpoint")
-	alchemyApiKey := flag.String("alchemyApiKey", "", "alchemy api key")
-	useAlchemy := flag.Bool("useAlchemy", true, "use alchemy")
-	maxQueryBlocks := flag.Uint64("maxQueryBlocks", 2000, "max query blocks")
-	queryBatchSize := flag.Uint64("queryBatchSize", 200, "query batch size")
+	grpcPort := flag.String("grpcPort", "9090", "grpc port to listen on")
+	backend := registerBackendFlags(flag.CommandLine)
+	subscribePollInterval := flag.Duration("subscribePollInterval", 2*time.Second, "interval between sealed head queries for subscriptions")
+	subscribeOrigins := flag.String("subscribeOrigins", "", "comma separated origins allowed to open WebSocket subscriptions besides the own one, * allows any")
 	flag.Parse()
+	allowedOrigins = parseOrigins(*subscribeOrigins)
 
-	// check if useAlchemy
-	if *useAlchemy {
-		if *alchemyEndpoint == "" {
-			log.Fatal("alchemy endpoint is required")
-		}
-		if *alchemyApiKey == "" {
-			log.Fatal("alchemy api key is required")
-		}
-		flowClient = spork.NewSporkAlchemy(*alchemyEndpoint, *alchemyApiKey, *maxQueryBlocks, *queryBatchSize)
-
-	} else {
-		flowClient = spork.NewSporkStore(*stage, *maxQueryBlocks, *queryBatchSize)
-	}
+	flowClient = backend.newFlowClient()
 
 	// display formatted sporkStore configuration
 	log.Info(fmt.Sprintf("sporkStore configuration: %s", flowClient.String()))
+
+	subscriptionHub = spork.NewSubscriptionHub(flowClient, *subscribePollInterval, *backend.maxQueryBlocks)
+	subscriptionHub.Start()
 	router := gin.Default()
 
 	router.Use(gin.LoggerWithWriter(os.Stderr))
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	router.GET("/version", version)
-	router.GET("/syncSpork", syncSpork)
-	router.POST("/queryEventByBlockRange", queryEventByBlockRange)
-	router.GET("/queryLatestBlockHeight", queryLatestBlockHeight)
+	registerV1Routes(router)
+	registerV1Routes(router.Group("/v1"))
+	registerV2Routes(router.Group("/v2"))
+
+	lis, err := net.Listen("tcp", ":"+*grpcPort)
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := server.NewGRPCServer(flowClient, subscriptionHub, backendMode)
+	go func() {
+		log.Info("Starting grpc server on ", *grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	log.Info("Starting server...")
 	router.Run(":" + *port)
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the value as {"type": ..., "value": ...}. Arrays become
+// JSON arrays, dictionaries lists of key/value objects and composites nested
+// objects keyed by field name, in declaration order.
+func (x *CadenceValue) MarshalJSON() ([]byte, error) {
+	if x == nil {
+		return []byte("null"), nil
+	}
+
+	var value interface{}
+	kind := ""
+	switch v := x.GetValue().(type) {
+	case *CadenceValue_Scalar:
+		value = v.Scalar
+	case *CadenceValue_Boolean:
+		value = v.Boolean
+	case *CadenceValue_Optional:
+		value = v.Optional.GetValue()
+	case *CadenceValue_Array:
+		values := v.Array.GetValues()
+		if values == nil {
+			values = []*CadenceValue{}
+		}
+		value = values
+	case *CadenceValue_Dictionary:
+		entries := make([]map[string]*CadenceValue, 0, len(v.Dictionary.GetEntries()))
+		for _, entry := range v.Dictionary.GetEntries() {
+			entries = append(entries, map[string]*CadenceValue{
+				"key":   entry.GetKey(),
+				"value": entry.GetValue(),
+			})
+		}
+		value = entries
+	case *CadenceValue_Composite:
+		kind = v.Composite.GetKind()
+		fields, err := marshalCadenceFields(v.Composite.GetFields())
+		if err != nil {
+			return nil, err
+		}
+		value = fields
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	typeJSON, _ := json.Marshal(x.GetType())
+	buf.Write(typeJSON)
+	if kind != "" {
+		buf.WriteString(`,"kind":`)
+		kindJSON, _ := json.Marshal(kind)
+		buf.Write(kindJSON)
+	}
+	buf.WriteString(`,"value":`)
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(valueJSON)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalCadenceFields encodes composite fields as an object, keeping field order
+func marshalCadenceFields(fields []*CadenceField) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(field.GetName())
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.GetValue())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,15 @@
+package v1
+
+// EventTypes returns the deduplicated event types of the request, event first
+func (x *QueryEventByBlockRangeRequest) EventTypes() []string {
+	eventTypes := make([]string, 0, len(x.GetEvents())+1)
+	seen := make(map[string]bool)
+	for _, eventType := range append([]string{x.GetEvent()}, x.GetEvents()...) {
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		eventTypes = append(eventTypes, eventType)
+	}
+	return eventTypes
+}
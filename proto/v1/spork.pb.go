@@ -211,6 +211,25 @@ type QueryEventByBlockRangeRequest struct {
 	Event string `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
 	Start uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
 	End   uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	// events queries several event types at once, together with event if both are set
+	Events []string `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	// includePayload attaches the raw JSON-CDC payload emitted by Flow to every event
+	IncludePayload bool `protobuf:"varint,5,opt,name=includePayload,proto3" json:"includePayload,omitempty"`
+	// partial returns the events fetched so far instead of failing when some blocks cannot be fetched,
+	// the missing ranges are listed in failedRanges. Only applies to QueryEventByBlockRange.
+	Partial bool `protobuf:"varint,6,opt,name=partial,proto3" json:"partial,omitempty"`
+	// limit pages QueryEventByBlockRange by event count, 0 returns every event of the range
+	Limit uint32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor is the nextCursor of the previous page, empty starts at start
+	Cursor string `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// startTime and endTime select the blocks of the window [startTime, endTime) instead of start and end,
+	// both are RFC 3339 timestamps, e.g. 2022-03-01T00:00:00Z
+	StartTime string `protobuf:"bytes,9,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime   string `protobuf:"bytes,10,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	// enrich attaches the block and transaction of every event, each fetched once per request
+	Enrich bool `protobuf:"varint,11,opt,name=enrich,proto3" json:"enrich,omitempty"`
+	// filter only returns the events whose fields match it, evaluated before limit counts events
+	Filter *EventFilter `protobuf:"bytes,12,opt,name=filter,proto3" json:"filter,omitempty"`
 }
 
 func (x *QueryEventByBlockRangeRequest) Reset() {
@@ -266,18 +285,195 @@ func (x *QueryEventByBlockRangeRequest) GetEnd() uint64 {
 	return 0
 }
 
+func (x *QueryEventByBlockRangeRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeRequest) GetIncludePayload() bool {
+	if x != nil {
+		return x.IncludePayload
+	}
+	return false
+}
+
+func (x *QueryEventByBlockRangeRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *QueryEventByBlockRangeRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryEventByBlockRangeRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeRequest) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeRequest) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeRequest) GetEnrich() bool {
+	if x != nil {
+		return x.Enrich
+	}
+	return false
+}
+
+func (x *QueryEventByBlockRangeRequest) GetFilter() *EventFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// EventFilter matches the decoded fields of an event. A condition on field holds when every operator set on it does,
+// the conditions in and must all hold and at least one of those in or, when any is given.
+type EventFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// field is the name of a top-level field of the event, e.g. to
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// eq compares numbers by value and addresses with or without 0x
+	Eq string `protobuf:"bytes,2,opt,name=eq,proto3" json:"eq,omitempty"`
+	// in holds when the field equals one of the values
+	In []string `protobuf:"bytes,3,rep,name=in,proto3" json:"in,omitempty"`
+	// gte and lte bound a numeric field, both inclusive
+	Gte string `protobuf:"bytes,4,opt,name=gte,proto3" json:"gte,omitempty"`
+	Lte string `protobuf:"bytes,5,opt,name=lte,proto3" json:"lte,omitempty"`
+	// prefix matches the start of the field as text, addresses in lowercase hex with 0x
+	Prefix string         `protobuf:"bytes,6,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	And    []*EventFilter `protobuf:"bytes,7,rep,name=and,proto3" json:"and,omitempty"`
+	Or     []*EventFilter `protobuf:"bytes,8,rep,name=or,proto3" json:"or,omitempty"`
+}
+
+func (x *EventFilter) Reset() {
+	*x = EventFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventFilter) ProtoMessage() {}
+
+func (x *EventFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventFilter.ProtoReflect.Descriptor instead.
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EventFilter) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *EventFilter) GetEq() string {
+	if x != nil {
+		return x.Eq
+	}
+	return ""
+}
+
+func (x *EventFilter) GetIn() []string {
+	if x != nil {
+		return x.In
+	}
+	return nil
+}
+
+func (x *EventFilter) GetGte() string {
+	if x != nil {
+		return x.Gte
+	}
+	return ""
+}
+
+func (x *EventFilter) GetLte() string {
+	if x != nil {
+		return x.Lte
+	}
+	return ""
+}
+
+func (x *EventFilter) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *EventFilter) GetAnd() []*EventFilter {
+	if x != nil {
+		return x.And
+	}
+	return nil
+}
+
+func (x *EventFilter) GetOr() []*EventFilter {
+	if x != nil {
+		return x.Or
+	}
+	return nil
+}
+
 type QueryEventByBlockRangeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Events []*QueryEventByBlockRangeResponseEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// failedRanges are the block ranges missing from events, only set in partial mode
+	FailedRanges []*FailedHeightRange `protobuf:"bytes,2,rep,name=failedRanges,proto3" json:"failedRanges,omitempty"`
+	// nextCursor fetches the next page when limit is set, empty once the range is done
+	NextCursor string `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
 }
 
 func (x *QueryEventByBlockRangeResponse) Reset() {
 	*x = QueryEventByBlockRangeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_v1_spork_proto_msgTypes[5]
+		mi := &file_proto_v1_spork_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -290,7 +486,7 @@ func (x *QueryEventByBlockRangeResponse) String() string {
 func (*QueryEventByBlockRangeResponse) ProtoMessage() {}
 
 func (x *QueryEventByBlockRangeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_spork_proto_msgTypes[5]
+	mi := &file_proto_v1_spork_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -303,7 +499,7 @@ func (x *QueryEventByBlockRangeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryEventByBlockRangeResponse.ProtoReflect.Descriptor instead.
 func (*QueryEventByBlockRangeResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_spork_proto_rawDescGZIP(), []int{5}
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *QueryEventByBlockRangeResponse) GetEvents() []*QueryEventByBlockRangeResponseEvent {
@@ -313,6 +509,84 @@ func (x *QueryEventByBlockRangeResponse) GetEvents() []*QueryEventByBlockRangeRe
 	return nil
 }
 
+func (x *QueryEventByBlockRangeResponse) GetFailedRanges() []*FailedHeightRange {
+	if x != nil {
+		return x.FailedRanges
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// FailedHeightRange is a block range [start, end] that could not be fetched
+type FailedHeightRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start uint64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *FailedHeightRange) Reset() {
+	*x = FailedHeightRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailedHeightRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedHeightRange) ProtoMessage() {}
+
+func (x *FailedHeightRange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedHeightRange.ProtoReflect.Descriptor instead.
+func (*FailedHeightRange) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FailedHeightRange) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *FailedHeightRange) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *FailedHeightRange) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type QueryEventByBlockRangeResponseEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -326,12 +600,20 @@ type QueryEventByBlockRangeResponseEvent struct {
 	TransactionIndex int64                                  `protobuf:"varint,6,opt,name=transactionIndex,proto3" json:"transactionIndex,omitempty"`
 	Timestamp        *timestamppb.Timestamp                 `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Values           []*QueryEventByBlockRangeResponseValue `protobuf:"bytes,8,rep,name=values,proto3" json:"values,omitempty"`
+	// payload is the JSON-CDC encoded event as returned by the access node,
+	// only set when includePayload is requested
+	Payload []byte `protobuf:"bytes,9,opt,name=payload,proto3" json:"payload,omitempty"`
+	// payloadJson is the same JSON-CDC document as a string, JSON clients get it escaped and decode it once more
+	PayloadJson string `protobuf:"bytes,10,opt,name=payloadJson,proto3" json:"payloadJson,omitempty"`
+	// block and transaction are only set when enrich is requested
+	Block       *BlockContext       `protobuf:"bytes,11,opt,name=block,proto3" json:"block,omitempty"`
+	Transaction *TransactionContext `protobuf:"bytes,12,opt,name=transaction,proto3" json:"transaction,omitempty"`
 }
 
 func (x *QueryEventByBlockRangeResponseEvent) Reset() {
 	*x = QueryEventByBlockRangeResponseEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_v1_spork_proto_msgTypes[6]
+		mi := &file_proto_v1_spork_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -344,7 +626,7 @@ func (x *QueryEventByBlockRangeResponseEvent) String() string {
 func (*QueryEventByBlockRangeResponseEvent) ProtoMessage() {}
 
 func (x *QueryEventByBlockRangeResponseEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_spork_proto_msgTypes[6]
+	mi := &file_proto_v1_spork_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -357,7 +639,7 @@ func (x *QueryEventByBlockRangeResponseEvent) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use QueryEventByBlockRangeResponseEvent.ProtoReflect.Descriptor instead.
 func (*QueryEventByBlockRangeResponseEvent) Descriptor() ([]byte, []int) {
-	return file_proto_v1_spork_proto_rawDescGZIP(), []int{6}
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *QueryEventByBlockRangeResponseEvent) GetBlockId() uint64 {
@@ -416,32 +698,61 @@ func (x *QueryEventByBlockRangeResponseEvent) GetValues() []*QueryEventByBlockRa
 	return nil
 }
 
-type QueryEventByBlockRangeResponseValue struct {
+func (x *QueryEventByBlockRangeResponseEvent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeResponseEvent) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeResponseEvent) GetBlock() *BlockContext {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeResponseEvent) GetTransaction() *TransactionContext {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+// BlockContext identifies the block of an enriched event
+type BlockContext struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId string `protobuf:"bytes,2,opt,name=parentId,proto3" json:"parentId,omitempty"`
 }
 
-func (x *QueryEventByBlockRangeResponseValue) Reset() {
-	*x = QueryEventByBlockRangeResponseValue{}
+func (x *BlockContext) Reset() {
+	*x = BlockContext{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_v1_spork_proto_msgTypes[7]
+		mi := &file_proto_v1_spork_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryEventByBlockRangeResponseValue) String() string {
+func (x *BlockContext) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryEventByBlockRangeResponseValue) ProtoMessage() {}
+func (*BlockContext) ProtoMessage() {}
 
-func (x *QueryEventByBlockRangeResponseValue) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_spork_proto_msgTypes[7]
+func (x *BlockContext) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,48 +763,57 @@ func (x *QueryEventByBlockRangeResponseValue) ProtoReflect() protoreflect.Messag
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryEventByBlockRangeResponseValue.ProtoReflect.Descriptor instead.
-func (*QueryEventByBlockRangeResponseValue) Descriptor() ([]byte, []int) {
-	return file_proto_v1_spork_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use BlockContext.ProtoReflect.Descriptor instead.
+func (*BlockContext) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *QueryEventByBlockRangeResponseValue) GetName() string {
+func (x *BlockContext) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-func (x *QueryEventByBlockRangeResponseValue) GetValue() string {
+func (x *BlockContext) GetParentId() string {
 	if x != nil {
-		return x.Value
+		return x.ParentId
 	}
 	return ""
 }
 
-type QueryLatestBlockHeightRequest struct {
+// TransactionContext describes the transaction of an enriched event
+type TransactionContext struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Payer       string   `protobuf:"bytes,1,opt,name=payer,proto3" json:"payer,omitempty"`
+	Proposer    string   `protobuf:"bytes,2,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	Authorizers []string `protobuf:"bytes,3,rep,name=authorizers,proto3" json:"authorizers,omitempty"`
+	// status is the transaction status, e.g. SEALED
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// errorMessage is set when the transaction reverted
+	ErrorMessage string `protobuf:"bytes,5,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
 }
 
-func (x *QueryLatestBlockHeightRequest) Reset() {
-	*x = QueryLatestBlockHeightRequest{}
+func (x *TransactionContext) Reset() {
+	*x = TransactionContext{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_v1_spork_proto_msgTypes[8]
+		mi := &file_proto_v1_spork_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryLatestBlockHeightRequest) String() string {
+func (x *TransactionContext) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryLatestBlockHeightRequest) ProtoMessage() {}
+func (*TransactionContext) ProtoMessage() {}
 
-func (x *QueryLatestBlockHeightRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_spork_proto_msgTypes[8]
+func (x *TransactionContext) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -504,205 +824,1651 @@ func (x *QueryLatestBlockHeightRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryLatestBlockHeightRequest.ProtoReflect.Descriptor instead.
-func (*QueryLatestBlockHeightRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_spork_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use TransactionContext.ProtoReflect.Descriptor instead.
+func (*TransactionContext) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{10}
 }
 
-type QueryLatestBlockHeightResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	LatestBlockHeight uint64 `protobuf:"varint,1,opt,name=latestBlockHeight,proto3" json:"latestBlockHeight,omitempty"`
+func (x *TransactionContext) GetPayer() string {
+	if x != nil {
+		return x.Payer
+	}
+	return ""
 }
 
-func (x *QueryLatestBlockHeightResponse) Reset() {
-	*x = QueryLatestBlockHeightResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_v1_spork_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *TransactionContext) GetProposer() string {
+	if x != nil {
+		return x.Proposer
 	}
+	return ""
 }
 
-func (x *QueryLatestBlockHeightResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *TransactionContext) GetAuthorizers() []string {
+	if x != nil {
+		return x.Authorizers
+	}
+	return nil
+}
+
+func (x *TransactionContext) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransactionContext) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type QueryEventByBlockRangeResponseValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// type is the declared Cadence type of the field
+	Type       string        `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	TypedValue *CadenceValue `protobuf:"bytes,4,opt,name=typedValue,proto3" json:"typedValue,omitempty"`
+}
+
+func (x *QueryEventByBlockRangeResponseValue) Reset() {
+	*x = QueryEventByBlockRangeResponseValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryEventByBlockRangeResponseValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryEventByBlockRangeResponseValue) ProtoMessage() {}
+
+func (x *QueryEventByBlockRangeResponseValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryEventByBlockRangeResponseValue.ProtoReflect.Descriptor instead.
+func (*QueryEventByBlockRangeResponseValue) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QueryEventByBlockRangeResponseValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeResponseValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeResponseValue) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *QueryEventByBlockRangeResponseValue) GetTypedValue() *CadenceValue {
+	if x != nil {
+		return x.TypedValue
+	}
+	return nil
+}
+
+// CadenceValue is a structured Cadence value. Numbers, addresses, strings and
+// paths are kept as scalar strings so no precision is lost, type tells them apart.
+type CadenceValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Types that are assignable to Value:
+	//	*CadenceValue_Scalar
+	//	*CadenceValue_Boolean
+	//	*CadenceValue_Optional
+	//	*CadenceValue_Array
+	//	*CadenceValue_Dictionary
+	//	*CadenceValue_Composite
+	Value isCadenceValue_Value `protobuf_oneof:"value"`
+}
+
+func (x *CadenceValue) Reset() {
+	*x = CadenceValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceValue) ProtoMessage() {}
+
+func (x *CadenceValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceValue.ProtoReflect.Descriptor instead.
+func (*CadenceValue) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CadenceValue) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (m *CadenceValue) GetValue() isCadenceValue_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *CadenceValue) GetScalar() string {
+	if x, ok := x.GetValue().(*CadenceValue_Scalar); ok {
+		return x.Scalar
+	}
+	return ""
+}
+
+func (x *CadenceValue) GetBoolean() bool {
+	if x, ok := x.GetValue().(*CadenceValue_Boolean); ok {
+		return x.Boolean
+	}
+	return false
+}
+
+func (x *CadenceValue) GetOptional() *CadenceOptional {
+	if x, ok := x.GetValue().(*CadenceValue_Optional); ok {
+		return x.Optional
+	}
+	return nil
+}
+
+func (x *CadenceValue) GetArray() *CadenceArray {
+	if x, ok := x.GetValue().(*CadenceValue_Array); ok {
+		return x.Array
+	}
+	return nil
+}
+
+func (x *CadenceValue) GetDictionary() *CadenceDictionary {
+	if x, ok := x.GetValue().(*CadenceValue_Dictionary); ok {
+		return x.Dictionary
+	}
+	return nil
+}
+
+func (x *CadenceValue) GetComposite() *CadenceComposite {
+	if x, ok := x.GetValue().(*CadenceValue_Composite); ok {
+		return x.Composite
+	}
+	return nil
+}
+
+type isCadenceValue_Value interface {
+	isCadenceValue_Value()
+}
+
+type CadenceValue_Scalar struct {
+	Scalar string `protobuf:"bytes,2,opt,name=scalar,proto3,oneof"`
+}
+
+type CadenceValue_Boolean struct {
+	Boolean bool `protobuf:"varint,3,opt,name=boolean,proto3,oneof"`
+}
+
+type CadenceValue_Optional struct {
+	Optional *CadenceOptional `protobuf:"bytes,4,opt,name=optional,proto3,oneof"`
+}
+
+type CadenceValue_Array struct {
+	Array *CadenceArray `protobuf:"bytes,5,opt,name=array,proto3,oneof"`
+}
+
+type CadenceValue_Dictionary struct {
+	Dictionary *CadenceDictionary `protobuf:"bytes,6,opt,name=dictionary,proto3,oneof"`
+}
+
+type CadenceValue_Composite struct {
+	Composite *CadenceComposite `protobuf:"bytes,7,opt,name=composite,proto3,oneof"`
+}
+
+func (*CadenceValue_Scalar) isCadenceValue_Value() {}
+
+func (*CadenceValue_Boolean) isCadenceValue_Value() {}
+
+func (*CadenceValue_Optional) isCadenceValue_Value() {}
+
+func (*CadenceValue_Array) isCadenceValue_Value() {}
+
+func (*CadenceValue_Dictionary) isCadenceValue_Value() {}
+
+func (*CadenceValue_Composite) isCadenceValue_Value() {}
+
+// CadenceOptional is a Cadence optional, value is unset for nil
+type CadenceOptional struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value *CadenceValue `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *CadenceOptional) Reset() {
+	*x = CadenceOptional{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceOptional) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceOptional) ProtoMessage() {}
+
+func (x *CadenceOptional) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceOptional.ProtoReflect.Descriptor instead.
+func (*CadenceOptional) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CadenceOptional) GetValue() *CadenceValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type CadenceArray struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []*CadenceValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *CadenceArray) Reset() {
+	*x = CadenceArray{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceArray) ProtoMessage() {}
+
+func (x *CadenceArray) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceArray.ProtoReflect.Descriptor instead.
+func (*CadenceArray) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CadenceArray) GetValues() []*CadenceValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CadenceDictionary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*CadenceKeyValue `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *CadenceDictionary) Reset() {
+	*x = CadenceDictionary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceDictionary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceDictionary) ProtoMessage() {}
+
+func (x *CadenceDictionary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceDictionary.ProtoReflect.Descriptor instead.
+func (*CadenceDictionary) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CadenceDictionary) GetEntries() []*CadenceKeyValue {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type CadenceKeyValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   *CadenceValue `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value *CadenceValue `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *CadenceKeyValue) Reset() {
+	*x = CadenceKeyValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceKeyValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceKeyValue) ProtoMessage() {}
+
+func (x *CadenceKeyValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceKeyValue.ProtoReflect.Descriptor instead.
+func (*CadenceKeyValue) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CadenceKeyValue) GetKey() *CadenceValue {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *CadenceKeyValue) GetValue() *CadenceValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// CadenceComposite is a struct, resource, event, contract or enum value
+type CadenceComposite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind   string          `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Fields []*CadenceField `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *CadenceComposite) Reset() {
+	*x = CadenceComposite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceComposite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceComposite) ProtoMessage() {}
+
+func (x *CadenceComposite) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceComposite.ProtoReflect.Descriptor instead.
+func (*CadenceComposite) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CadenceComposite) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CadenceComposite) GetFields() []*CadenceField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type CadenceField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value *CadenceValue `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *CadenceField) Reset() {
+	*x = CadenceField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CadenceField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CadenceField) ProtoMessage() {}
+
+func (x *CadenceField) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CadenceField.ProtoReflect.Descriptor instead.
+func (*CadenceField) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CadenceField) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CadenceField) GetValue() *CadenceValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// StreamEventsByBlockRangeResponse is one fetched batch, covering blocks [start, end].
+// A disconnected client can resume by querying again from cursor.
+type StreamEventsByBlockRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start  uint64                                 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End    uint64                                 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Cursor uint64                                 `protobuf:"varint,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Events []*QueryEventByBlockRangeResponseEvent `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *StreamEventsByBlockRangeResponse) Reset() {
+	*x = StreamEventsByBlockRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamEventsByBlockRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsByBlockRangeResponse) ProtoMessage() {}
+
+func (x *StreamEventsByBlockRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsByBlockRangeResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsByBlockRangeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamEventsByBlockRangeResponse) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *StreamEventsByBlockRangeResponse) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *StreamEventsByBlockRangeResponse) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *StreamEventsByBlockRangeResponse) GetEvents() []*QueryEventByBlockRangeResponseEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// SubscribeEventsRequest follows the sealed head from start, start 0 means the current sealed head.
+type SubscribeEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []string `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	Start  uint64   `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+}
+
+func (x *SubscribeEventsRequest) Reset() {
+	*x = SubscribeEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SubscribeEventsRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *SubscribeEventsRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+type QueryLatestBlockHeightRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *QueryLatestBlockHeightRequest) Reset() {
+	*x = QueryLatestBlockHeightRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryLatestBlockHeightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryLatestBlockHeightRequest) ProtoMessage() {}
+
+func (x *QueryLatestBlockHeightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryLatestBlockHeightRequest.ProtoReflect.Descriptor instead.
+func (*QueryLatestBlockHeightRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{21}
+}
+
+type QueryLatestBlockHeightResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LatestBlockHeight uint64 `protobuf:"varint,1,opt,name=latestBlockHeight,proto3" json:"latestBlockHeight,omitempty"`
+}
+
+func (x *QueryLatestBlockHeightResponse) Reset() {
+	*x = QueryLatestBlockHeightResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryLatestBlockHeightResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*QueryLatestBlockHeightResponse) ProtoMessage() {}
 
-func (x *QueryLatestBlockHeightResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_spork_proto_msgTypes[9]
+func (x *QueryLatestBlockHeightResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryLatestBlockHeightResponse.ProtoReflect.Descriptor instead.
+func (*QueryLatestBlockHeightResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *QueryLatestBlockHeightResponse) GetLatestBlockHeight() uint64 {
+	if x != nil {
+		return x.LatestBlockHeight
+	}
+	return 0
+}
+
+// QueryBlockAtTimeRequest looks up the first sealed block made at or after timestamp, an RFC 3339 timestamp
+type QueryBlockAtTimeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp string `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *QueryBlockAtTimeRequest) Reset() {
+	*x = QueryBlockAtTimeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockAtTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockAtTimeRequest) ProtoMessage() {}
+
+func (x *QueryBlockAtTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockAtTimeRequest.ProtoReflect.Descriptor instead.
+func (*QueryBlockAtTimeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *QueryBlockAtTimeRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type QueryBlockAtTimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHeight uint64                 `protobuf:"varint,1,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	BlockId     string                 `protobuf:"bytes,2,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *QueryBlockAtTimeResponse) Reset() {
+	*x = QueryBlockAtTimeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockAtTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockAtTimeResponse) ProtoMessage() {}
+
+func (x *QueryBlockAtTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockAtTimeResponse.ProtoReflect.Descriptor instead.
+func (*QueryBlockAtTimeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *QueryBlockAtTimeResponse) GetBlockHeight() uint64 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
+func (x *QueryBlockAtTimeResponse) GetBlockId() string {
+	if x != nil {
+		return x.BlockId
+	}
+	return ""
+}
+
+func (x *QueryBlockAtTimeResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// AggregateEventsRequest counts the events selected by query, limit, cursor and partial are not supported
+type AggregateEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *QueryEventByBlockRangeRequest `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// field is the numeric field, e.g. amount, summed with its min and max; empty only counts events
+	Field string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	// groupBy groups the events by the value of this field
+	GroupBy string `protobuf:"bytes,3,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
+	// bucket groups the events by block time, a duration such as 1h or 15m
+	Bucket string `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+}
+
+func (x *AggregateEventsRequest) Reset() {
+	*x = AggregateEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateEventsRequest) ProtoMessage() {}
+
+func (x *AggregateEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateEventsRequest.ProtoReflect.Descriptor instead.
+func (*AggregateEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AggregateEventsRequest) GetQuery() *QueryEventByBlockRangeRequest {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *AggregateEventsRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *AggregateEventsRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *AggregateEventsRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type AggregateEventsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// groups are ordered by bucket, then by key
+	Groups []*EventAggregate `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+}
+
+func (x *AggregateEventsResponse) Reset() {
+	*x = AggregateEventsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateEventsResponse) ProtoMessage() {}
+
+func (x *AggregateEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateEventsResponse.ProtoReflect.Descriptor instead.
+func (*AggregateEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AggregateEventsResponse) GetGroups() []*EventAggregate {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// EventAggregate sums up the events of one group
+type EventAggregate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// key is the groupBy value of the group, empty when the events are not grouped by field or lack it
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// bucketStart is the start of the time bucket, only set with bucket
+	BucketStart *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=bucketStart,proto3" json:"bucketStart,omitempty"`
+	Count       uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// sum, min and max are decimal strings over the events holding a number in field, empty without one
+	Sum string `protobuf:"bytes,4,opt,name=sum,proto3" json:"sum,omitempty"`
+	Min string `protobuf:"bytes,5,opt,name=min,proto3" json:"min,omitempty"`
+	Max string `protobuf:"bytes,6,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *EventAggregate) Reset() {
+	*x = EventAggregate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v1_spork_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventAggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAggregate) ProtoMessage() {}
+
+func (x *EventAggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_spork_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use QueryLatestBlockHeightResponse.ProtoReflect.Descriptor instead.
-func (*QueryLatestBlockHeightResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_spork_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *QueryLatestBlockHeightResponse) GetLatestBlockHeight() uint64 {
-	if x != nil {
-		return x.LatestBlockHeight
-	}
-	return 0
-}
-
-var File_proto_v1_spork_proto protoreflect.FileDescriptor
-
-var file_proto_v1_spork_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x70, 0x6f, 0x72, 0x6b,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31,
-	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x12, 0x20, 0x0a, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x6f,
-	0x64, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x29, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70,
-	0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
-	0x70, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x72,
-	0x6b, 0x22, 0x5d, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
-	0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64,
-	0x22, 0x67, 0x0a, 0x1e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x45, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xd6, 0x02, 0x0a, 0x23, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76,
-	0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
-	0x24, 0x0a, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65,
-	0x78, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x45, 0x0a, 0x06, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x73, 0x22, 0x4f, 0x0a, 0x23, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4c, 0x61, 0x74, 0x65,
-	0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x4e, 0x0a, 0x1e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4c, 0x61, 0x74,
-	0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65,
-	0x69, 0x67, 0x68, 0x74, 0x32, 0xef, 0x02, 0x0a, 0x05, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x12, 0x40,
-	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x46, 0x0a, 0x09, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x12, 0x1a, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f,
-	0x72, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6d, 0x0a, 0x16, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e,
-	0x67, 0x65, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6d, 0x0a, 0x16, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x73,
-	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x4f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x76, 0x31, 0x42, 0x0a, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
-	0x5a, 0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x61, 0x74,
-	0x72, 0x69, 0x78, 0x4c, 0x61, 0x62, 0x73, 0x54, 0x65, 0x63, 0x68, 0x2f, 0x66, 0x6c, 0x6f, 0x77,
-	0x2d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2d, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_proto_v1_spork_proto_rawDescOnce sync.Once
-	file_proto_v1_spork_proto_rawDescData = file_proto_v1_spork_proto_rawDesc
-)
-
-func file_proto_v1_spork_proto_rawDescGZIP() []byte {
-	file_proto_v1_spork_proto_rawDescOnce.Do(func() {
-		file_proto_v1_spork_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_v1_spork_proto_rawDescData)
-	})
-	return file_proto_v1_spork_proto_rawDescData
-}
-
-var file_proto_v1_spork_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
-var file_proto_v1_spork_proto_goTypes = []interface{}{
-	(*VersionRequest)(nil),                      // 0: proto.v1.VersionRequest
-	(*VersionResponse)(nil),                     // 1: proto.v1.VersionResponse
-	(*SyncSporkRequest)(nil),                    // 2: proto.v1.SyncSporkRequest
-	(*SyncSporkResponse)(nil),                   // 3: proto.v1.SyncSporkResponse
-	(*QueryEventByBlockRangeRequest)(nil),       // 4: proto.v1.QueryEventByBlockRangeRequest
-	(*QueryEventByBlockRangeResponse)(nil),      // 5: proto.v1.QueryEventByBlockRangeResponse
-	(*QueryEventByBlockRangeResponseEvent)(nil), // 6: proto.v1.QueryEventByBlockRangeResponseEvent
-	(*QueryEventByBlockRangeResponseValue)(nil), // 7: proto.v1.QueryEventByBlockRangeResponseValue
-	(*QueryLatestBlockHeightRequest)(nil),       // 8: proto.v1.QueryLatestBlockHeightRequest
-	(*QueryLatestBlockHeightResponse)(nil),      // 9: proto.v1.QueryLatestBlockHeightResponse
-	(*timestamppb.Timestamp)(nil),               // 10: google.protobuf.Timestamp
-}
-var file_proto_v1_spork_proto_depIdxs = []int32{
-	6,  // 0: proto.v1.QueryEventByBlockRangeResponse.events:type_name -> proto.v1.QueryEventByBlockRangeResponseEvent
-	10, // 1: proto.v1.QueryEventByBlockRangeResponseEvent.timestamp:type_name -> google.protobuf.Timestamp
-	7,  // 2: proto.v1.QueryEventByBlockRangeResponseEvent.values:type_name -> proto.v1.QueryEventByBlockRangeResponseValue
-	0,  // 3: proto.v1.Spork.Version:input_type -> proto.v1.VersionRequest
-	2,  // 4: proto.v1.Spork.SyncSpork:input_type -> proto.v1.SyncSporkRequest
-	4,  // 5: proto.v1.Spork.QueryEventByBlockRange:input_type -> proto.v1.QueryEventByBlockRangeRequest
-	8,  // 6: proto.v1.Spork.QueryLatestBlockHeight:input_type -> proto.v1.QueryLatestBlockHeightRequest
-	1,  // 7: proto.v1.Spork.Version:output_type -> proto.v1.VersionResponse
-	3,  // 8: proto.v1.Spork.SyncSpork:output_type -> proto.v1.SyncSporkResponse
-	5,  // 9: proto.v1.Spork.QueryEventByBlockRange:output_type -> proto.v1.QueryEventByBlockRangeResponse
-	9,  // 10: proto.v1.Spork.QueryLatestBlockHeight:output_type -> proto.v1.QueryLatestBlockHeightResponse
-	7,  // [7:11] is the sub-list for method output_type
-	3,  // [3:7] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
-}
-
-func init() { file_proto_v1_spork_proto_init() }
-func file_proto_v1_spork_proto_init() {
-	if File_proto_v1_spork_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_v1_spork_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VersionRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAggregate.ProtoReflect.Descriptor instead.
+func (*EventAggregate) Descriptor() ([]byte, []int) {
+	return file_proto_v1_spork_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EventAggregate) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *EventAggregate) GetBucketStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BucketStart
+	}
+	return nil
+}
+
+func (x *EventAggregate) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *EventAggregate) GetSum() string {
+	if x != nil {
+		return x.Sum
+	}
+	return ""
+}
+
+func (x *EventAggregate) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *EventAggregate) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+var File_proto_v1_spork_proto protoreflect.FileDescriptor
+
+var file_proto_v1_spork_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x70, 0x6f, 0x72, 0x6b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x20, 0x0a, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4d, 0x6f,
+	0x64, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x29, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70,
+	0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x70, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x72,
+	0x6b, 0x22, 0xe4, 0x02, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e, 0x72, 0x69, 0x63, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x65, 0x6e, 0x72, 0x69, 0x63, 0x68, 0x12, 0x2d, 0x0a, 0x06, 0x66, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0xcf, 0x01, 0x0a, 0x0b, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x0e,
+	0x0a, 0x02, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x65, 0x71, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x69, 0x6e, 0x12, 0x10,
+	0x0a, 0x03, 0x67, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x67, 0x74, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x6c, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c,
+	0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x27, 0x0a, 0x03, 0x61, 0x6e,
+	0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x03,
+	0x61, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x02, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x02, 0x6f, 0x72, 0x22, 0xc8, 0x01, 0x0a, 0x1e, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a,
+	0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x48, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72,
+	0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x51, 0x0a, 0x11, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x48,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65,
+	0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x80, 0x04, 0x0a, 0x23, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x24,
+	0x0a, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x45, 0x0a, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x2c, 0x0a,
+	0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3e, 0x0a, 0x0b, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x0b,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3a, 0x0a, 0x0c, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xa4, 0x01, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x70, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72,
+	0x12, 0x20, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65,
+	0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9b,
+	0x01, 0x0a, 0x23, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x36, 0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x64, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc5, 0x02, 0x0a,
+	0x0c, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x1a, 0x0a, 0x07, 0x62,
+	0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07,
+	0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x12, 0x37, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
+	0x12, 0x2e, 0x0a, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e,
+	0x63, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x48, 0x00, 0x52, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79,
+	0x12, 0x3d, 0x0a, 0x0a, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72,
+	0x79, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x12,
+	0x3a, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x64, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x48, 0x00,
+	0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x3f, 0x0a, 0x0f, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x3e, 0x0a, 0x0c, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65,
+	0x41, 0x72, 0x72, 0x61, 0x79, 0x12, 0x2e, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x11, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65,
+	0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x33, 0x0a, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x4b, 0x65,
+	0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22,
+	0x69, 0x0a, 0x0f, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x28, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e,
+	0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x56, 0x0a, 0x10, 0x43, 0x61,
+	0x64, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x64, 0x65, 0x6e, 0x63, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x22, 0x50, 0x0a, 0x0c, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x61, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x20, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x45, 0x0a, 0x06, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
+	0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x46, 0x0a, 0x16, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0x1f, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4e, 0x0a, 0x1e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6c,
+	0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x37, 0x0a, 0x17, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x22, 0x90, 0x01, 0x0a, 0x18, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x41, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x9f, 0x01, 0x0a, 0x16, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x3d, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x22, 0x4b, 0x0a, 0x17, 0x41, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x06, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x0e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3c, 0x0a, 0x0b, 0x62, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x62, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x69,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6d, 0x61, 0x78, 0x32, 0x80, 0x06, 0x0a, 0x05, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x12, 0x40, 0x0a,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x46, 0x0a, 0x09, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x12, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6d, 0x0a, 0x16, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6d, 0x0a, 0x16, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4c,
+	0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x73, 0x0a, 0x18, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x63, 0x0a, 0x0f, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x5b, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0f,
+	0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67, 0x67,
+	0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x4f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x76, 0x31, 0x42, 0x0a, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
+	0x5a, 0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x61, 0x74,
+	0x72, 0x69, 0x78, 0x4c, 0x61, 0x62, 0x73, 0x54, 0x65, 0x63, 0x68, 0x2f, 0x66, 0x6c, 0x6f, 0x77,
+	0x2d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2d, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_v1_spork_proto_rawDescOnce sync.Once
+	file_proto_v1_spork_proto_rawDescData = file_proto_v1_spork_proto_rawDesc
+)
+
+func file_proto_v1_spork_proto_rawDescGZIP() []byte {
+	file_proto_v1_spork_proto_rawDescOnce.Do(func() {
+		file_proto_v1_spork_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_v1_spork_proto_rawDescData)
+	})
+	return file_proto_v1_spork_proto_rawDescData
+}
+
+var file_proto_v1_spork_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_proto_v1_spork_proto_goTypes = []interface{}{
+	(*VersionRequest)(nil),                      // 0: proto.v1.VersionRequest
+	(*VersionResponse)(nil),                     // 1: proto.v1.VersionResponse
+	(*SyncSporkRequest)(nil),                    // 2: proto.v1.SyncSporkRequest
+	(*SyncSporkResponse)(nil),                   // 3: proto.v1.SyncSporkResponse
+	(*QueryEventByBlockRangeRequest)(nil),       // 4: proto.v1.QueryEventByBlockRangeRequest
+	(*EventFilter)(nil),                         // 5: proto.v1.EventFilter
+	(*QueryEventByBlockRangeResponse)(nil),      // 6: proto.v1.QueryEventByBlockRangeResponse
+	(*FailedHeightRange)(nil),                   // 7: proto.v1.FailedHeightRange
+	(*QueryEventByBlockRangeResponseEvent)(nil), // 8: proto.v1.QueryEventByBlockRangeResponseEvent
+	(*BlockContext)(nil),                        // 9: proto.v1.BlockContext
+	(*TransactionContext)(nil),                  // 10: proto.v1.TransactionContext
+	(*QueryEventByBlockRangeResponseValue)(nil), // 11: proto.v1.QueryEventByBlockRangeResponseValue
+	(*CadenceValue)(nil),                        // 12: proto.v1.CadenceValue
+	(*CadenceOptional)(nil),                     // 13: proto.v1.CadenceOptional
+	(*CadenceArray)(nil),                        // 14: proto.v1.CadenceArray
+	(*CadenceDictionary)(nil),                   // 15: proto.v1.CadenceDictionary
+	(*CadenceKeyValue)(nil),                     // 16: proto.v1.CadenceKeyValue
+	(*CadenceComposite)(nil),                    // 17: proto.v1.CadenceComposite
+	(*CadenceField)(nil),                        // 18: proto.v1.CadenceField
+	(*StreamEventsByBlockRangeResponse)(nil),    // 19: proto.v1.StreamEventsByBlockRangeResponse
+	(*SubscribeEventsRequest)(nil),              // 20: proto.v1.SubscribeEventsRequest
+	(*QueryLatestBlockHeightRequest)(nil),       // 21: proto.v1.QueryLatestBlockHeightRequest
+	(*QueryLatestBlockHeightResponse)(nil),      // 22: proto.v1.QueryLatestBlockHeightResponse
+	(*QueryBlockAtTimeRequest)(nil),             // 23: proto.v1.QueryBlockAtTimeRequest
+	(*QueryBlockAtTimeResponse)(nil),            // 24: proto.v1.QueryBlockAtTimeResponse
+	(*AggregateEventsRequest)(nil),              // 25: proto.v1.AggregateEventsRequest
+	(*AggregateEventsResponse)(nil),             // 26: proto.v1.AggregateEventsResponse
+	(*EventAggregate)(nil),                      // 27: proto.v1.EventAggregate
+	(*timestamppb.Timestamp)(nil),               // 28: google.protobuf.Timestamp
+}
+var file_proto_v1_spork_proto_depIdxs = []int32{
+	5,  // 0: proto.v1.QueryEventByBlockRangeRequest.filter:type_name -> proto.v1.EventFilter
+	5,  // 1: proto.v1.EventFilter.and:type_name -> proto.v1.EventFilter
+	5,  // 2: proto.v1.EventFilter.or:type_name -> proto.v1.EventFilter
+	8,  // 3: proto.v1.QueryEventByBlockRangeResponse.events:type_name -> proto.v1.QueryEventByBlockRangeResponseEvent
+	7,  // 4: proto.v1.QueryEventByBlockRangeResponse.failedRanges:type_name -> proto.v1.FailedHeightRange
+	28, // 5: proto.v1.QueryEventByBlockRangeResponseEvent.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 6: proto.v1.QueryEventByBlockRangeResponseEvent.values:type_name -> proto.v1.QueryEventByBlockRangeResponseValue
+	9,  // 7: proto.v1.QueryEventByBlockRangeResponseEvent.block:type_name -> proto.v1.BlockContext
+	10, // 8: proto.v1.QueryEventByBlockRangeResponseEvent.transaction:type_name -> proto.v1.TransactionContext
+	12, // 9: proto.v1.QueryEventByBlockRangeResponseValue.typedValue:type_name -> proto.v1.CadenceValue
+	13, // 10: proto.v1.CadenceValue.optional:type_name -> proto.v1.CadenceOptional
+	14, // 11: proto.v1.CadenceValue.array:type_name -> proto.v1.CadenceArray
+	15, // 12: proto.v1.CadenceValue.dictionary:type_name -> proto.v1.CadenceDictionary
+	17, // 13: proto.v1.CadenceValue.composite:type_name -> proto.v1.CadenceComposite
+	12, // 14: proto.v1.CadenceOptional.value:type_name -> proto.v1.CadenceValue
+	12, // 15: proto.v1.CadenceArray.values:type_name -> proto.v1.CadenceValue
+	16, // 16: proto.v1.CadenceDictionary.entries:type_name -> proto.v1.CadenceKeyValue
+	12, // 17: proto.v1.CadenceKeyValue.key:type_name -> proto.v1.CadenceValue
+	12, // 18: proto.v1.CadenceKeyValue.value:type_name -> proto.v1.CadenceValue
+	18, // 19: proto.v1.CadenceComposite.fields:type_name -> proto.v1.CadenceField
+	12, // 20: proto.v1.CadenceField.value:type_name -> proto.v1.CadenceValue
+	8,  // 21: proto.v1.StreamEventsByBlockRangeResponse.events:type_name -> proto.v1.QueryEventByBlockRangeResponseEvent
+	28, // 22: proto.v1.QueryBlockAtTimeResponse.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 23: proto.v1.AggregateEventsRequest.query:type_name -> proto.v1.QueryEventByBlockRangeRequest
+	27, // 24: proto.v1.AggregateEventsResponse.groups:type_name -> proto.v1.EventAggregate
+	28, // 25: proto.v1.EventAggregate.bucketStart:type_name -> google.protobuf.Timestamp
+	0,  // 26: proto.v1.Spork.Version:input_type -> proto.v1.VersionRequest
+	2,  // 27: proto.v1.Spork.SyncSpork:input_type -> proto.v1.SyncSporkRequest
+	4,  // 28: proto.v1.Spork.QueryEventByBlockRange:input_type -> proto.v1.QueryEventByBlockRangeRequest
+	21, // 29: proto.v1.Spork.QueryLatestBlockHeight:input_type -> proto.v1.QueryLatestBlockHeightRequest
+	4,  // 30: proto.v1.Spork.StreamEventsByBlockRange:input_type -> proto.v1.QueryEventByBlockRangeRequest
+	20, // 31: proto.v1.Spork.SubscribeEvents:input_type -> proto.v1.SubscribeEventsRequest
+	23, // 32: proto.v1.Spork.QueryBlockAtTime:input_type -> proto.v1.QueryBlockAtTimeRequest
+	25, // 33: proto.v1.Spork.AggregateEvents:input_type -> proto.v1.AggregateEventsRequest
+	1,  // 34: proto.v1.Spork.Version:output_type -> proto.v1.VersionResponse
+	3,  // 35: proto.v1.Spork.SyncSpork:output_type -> proto.v1.SyncSporkResponse
+	6,  // 36: proto.v1.Spork.QueryEventByBlockRange:output_type -> proto.v1.QueryEventByBlockRangeResponse
+	22, // 37: proto.v1.Spork.QueryLatestBlockHeight:output_type -> proto.v1.QueryLatestBlockHeightResponse
+	19, // 38: proto.v1.Spork.StreamEventsByBlockRange:output_type -> proto.v1.StreamEventsByBlockRangeResponse
+	19, // 39: proto.v1.Spork.SubscribeEvents:output_type -> proto.v1.StreamEventsByBlockRangeResponse
+	24, // 40: proto.v1.Spork.QueryBlockAtTime:output_type -> proto.v1.QueryBlockAtTimeResponse
+	26, // 41: proto.v1.Spork.AggregateEvents:output_type -> proto.v1.AggregateEventsResponse
+	34, // [34:42] is the sub-list for method output_type
+	26, // [26:34] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_proto_v1_spork_proto_init() }
+func file_proto_v1_spork_proto_init() {
+	if File_proto_v1_spork_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_v1_spork_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncSporkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncSporkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryEventByBlockRangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryEventByBlockRangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailedHeightRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryEventByBlockRangeResponseEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryEventByBlockRangeResponseValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -713,8 +2479,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VersionResponse); i {
+		file_proto_v1_spork_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceOptional); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -725,8 +2491,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SyncSporkRequest); i {
+		file_proto_v1_spork_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceArray); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -737,8 +2503,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SyncSporkResponse); i {
+		file_proto_v1_spork_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceDictionary); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -749,8 +2515,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryEventByBlockRangeRequest); i {
+		file_proto_v1_spork_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceKeyValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -761,8 +2527,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryEventByBlockRangeResponse); i {
+		file_proto_v1_spork_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceComposite); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -773,8 +2539,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryEventByBlockRangeResponseEvent); i {
+		file_proto_v1_spork_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CadenceField); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -785,8 +2551,8 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryEventByBlockRangeResponseValue); i {
+		file_proto_v1_spork_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamEventsByBlockRangeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -797,7 +2563,19 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_v1_spork_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QueryLatestBlockHeightRequest); i {
 			case 0:
 				return &v.state
@@ -809,7 +2587,7 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
-		file_proto_v1_spork_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_v1_spork_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QueryLatestBlockHeightResponse); i {
 			case 0:
 				return &v.state
@@ -821,6 +2599,74 @@ func file_proto_v1_spork_proto_init() {
 				return nil
 			}
 		}
+		file_proto_v1_spork_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockAtTimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockAtTimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateEventsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v1_spork_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventAggregate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_v1_spork_proto_msgTypes[12].OneofWrappers = []interface{}{
+		(*CadenceValue_Scalar)(nil),
+		(*CadenceValue_Boolean)(nil),
+		(*CadenceValue_Optional)(nil),
+		(*CadenceValue_Array)(nil),
+		(*CadenceValue_Dictionary)(nil),
+		(*CadenceValue_Composite)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -828,7 +2674,7 @@ func file_proto_v1_spork_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_v1_spork_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -858,6 +2704,10 @@ type SporkClient interface {
 	SyncSpork(ctx context.Context, in *SyncSporkRequest, opts ...grpc.CallOption) (*SyncSporkResponse, error)
 	QueryEventByBlockRange(ctx context.Context, in *QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (*QueryEventByBlockRangeResponse, error)
 	QueryLatestBlockHeight(ctx context.Context, in *QueryLatestBlockHeightRequest, opts ...grpc.CallOption) (*QueryLatestBlockHeightResponse, error)
+	StreamEventsByBlockRange(ctx context.Context, in *QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (Spork_StreamEventsByBlockRangeClient, error)
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Spork_SubscribeEventsClient, error)
+	QueryBlockAtTime(ctx context.Context, in *QueryBlockAtTimeRequest, opts ...grpc.CallOption) (*QueryBlockAtTimeResponse, error)
+	AggregateEvents(ctx context.Context, in *AggregateEventsRequest, opts ...grpc.CallOption) (*AggregateEventsResponse, error)
 }
 
 type sporkClient struct {
@@ -904,12 +2754,98 @@ func (c *sporkClient) QueryLatestBlockHeight(ctx context.Context, in *QueryLates
 	return out, nil
 }
 
+func (c *sporkClient) StreamEventsByBlockRange(ctx context.Context, in *QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (Spork_StreamEventsByBlockRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Spork_serviceDesc.Streams[0], "/proto.v1.Spork/StreamEventsByBlockRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sporkStreamEventsByBlockRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Spork_StreamEventsByBlockRangeClient interface {
+	Recv() (*StreamEventsByBlockRangeResponse, error)
+	grpc.ClientStream
+}
+
+type sporkStreamEventsByBlockRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *sporkStreamEventsByBlockRangeClient) Recv() (*StreamEventsByBlockRangeResponse, error) {
+	m := new(StreamEventsByBlockRangeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sporkClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Spork_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Spork_serviceDesc.Streams[1], "/proto.v1.Spork/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sporkSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Spork_SubscribeEventsClient interface {
+	Recv() (*StreamEventsByBlockRangeResponse, error)
+	grpc.ClientStream
+}
+
+type sporkSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *sporkSubscribeEventsClient) Recv() (*StreamEventsByBlockRangeResponse, error) {
+	m := new(StreamEventsByBlockRangeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sporkClient) QueryBlockAtTime(ctx context.Context, in *QueryBlockAtTimeRequest, opts ...grpc.CallOption) (*QueryBlockAtTimeResponse, error) {
+	out := new(QueryBlockAtTimeResponse)
+	err := c.cc.Invoke(ctx, "/proto.v1.Spork/QueryBlockAtTime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporkClient) AggregateEvents(ctx context.Context, in *AggregateEventsRequest, opts ...grpc.CallOption) (*AggregateEventsResponse, error) {
+	out := new(AggregateEventsResponse)
+	err := c.cc.Invoke(ctx, "/proto.v1.Spork/AggregateEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SporkServer is the server API for Spork service.
 type SporkServer interface {
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
 	SyncSpork(context.Context, *SyncSporkRequest) (*SyncSporkResponse, error)
 	QueryEventByBlockRange(context.Context, *QueryEventByBlockRangeRequest) (*QueryEventByBlockRangeResponse, error)
 	QueryLatestBlockHeight(context.Context, *QueryLatestBlockHeightRequest) (*QueryLatestBlockHeightResponse, error)
+	StreamEventsByBlockRange(*QueryEventByBlockRangeRequest, Spork_StreamEventsByBlockRangeServer) error
+	SubscribeEvents(*SubscribeEventsRequest, Spork_SubscribeEventsServer) error
+	QueryBlockAtTime(context.Context, *QueryBlockAtTimeRequest) (*QueryBlockAtTimeResponse, error)
+	AggregateEvents(context.Context, *AggregateEventsRequest) (*AggregateEventsResponse, error)
 }
 
 // UnimplementedSporkServer can be embedded to have forward compatible implementations.
@@ -928,6 +2864,18 @@ func (*UnimplementedSporkServer) QueryEventByBlockRange(context.Context, *QueryE
 func (*UnimplementedSporkServer) QueryLatestBlockHeight(context.Context, *QueryLatestBlockHeightRequest) (*QueryLatestBlockHeightResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryLatestBlockHeight not implemented")
 }
+func (*UnimplementedSporkServer) StreamEventsByBlockRange(*QueryEventByBlockRangeRequest, Spork_StreamEventsByBlockRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEventsByBlockRange not implemented")
+}
+func (*UnimplementedSporkServer) SubscribeEvents(*SubscribeEventsRequest, Spork_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (*UnimplementedSporkServer) QueryBlockAtTime(context.Context, *QueryBlockAtTimeRequest) (*QueryBlockAtTimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlockAtTime not implemented")
+}
+func (*UnimplementedSporkServer) AggregateEvents(context.Context, *AggregateEventsRequest) (*AggregateEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateEvents not implemented")
+}
 
 func RegisterSporkServer(s *grpc.Server, srv SporkServer) {
 	s.RegisterService(&_Spork_serviceDesc, srv)
@@ -1005,6 +2953,84 @@ func _Spork_QueryLatestBlockHeight_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Spork_StreamEventsByBlockRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryEventByBlockRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SporkServer).StreamEventsByBlockRange(m, &sporkStreamEventsByBlockRangeServer{stream})
+}
+
+type Spork_StreamEventsByBlockRangeServer interface {
+	Send(*StreamEventsByBlockRangeResponse) error
+	grpc.ServerStream
+}
+
+type sporkStreamEventsByBlockRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *sporkStreamEventsByBlockRangeServer) Send(m *StreamEventsByBlockRangeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Spork_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SporkServer).SubscribeEvents(m, &sporkSubscribeEventsServer{stream})
+}
+
+type Spork_SubscribeEventsServer interface {
+	Send(*StreamEventsByBlockRangeResponse) error
+	grpc.ServerStream
+}
+
+type sporkSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *sporkSubscribeEventsServer) Send(m *StreamEventsByBlockRangeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Spork_QueryBlockAtTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBlockAtTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporkServer).QueryBlockAtTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.v1.Spork/QueryBlockAtTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporkServer).QueryBlockAtTime(ctx, req.(*QueryBlockAtTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spork_AggregateEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporkServer).AggregateEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.v1.Spork/AggregateEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporkServer).AggregateEvents(ctx, req.(*AggregateEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Spork_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.v1.Spork",
 	HandlerType: (*SporkServer)(nil),
@@ -1025,7 +3051,26 @@ var _Spork_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryLatestBlockHeight",
 			Handler:    _Spork_QueryLatestBlockHeight_Handler,
 		},
+		{
+			MethodName: "QueryBlockAtTime",
+			Handler:    _Spork_QueryBlockAtTime_Handler,
+		},
+		{
+			MethodName: "AggregateEvents",
+			Handler:    _Spork_AggregateEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEventsByBlockRange",
+			Handler:       _Spork_StreamEventsByBlockRange_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Spork_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/v1/spork.proto",
 }
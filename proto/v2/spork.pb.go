@@ -0,0 +1,737 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.4
+// source: proto/v2/spork.proto
+
+package v2
+
+import (
+	context "context"
+	v1 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QueryEventByBlockRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// failedRanges are the block ranges missing from events, only set in partial mode
+	FailedRanges []*v1.FailedHeightRange `protobuf:"bytes,2,rep,name=failedRanges,proto3" json:"failedRanges,omitempty"`
+	// nextCursor fetches the next page when limit is set, empty once the range is done
+	NextCursor string `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+}
+
+func (x *QueryEventByBlockRangeResponse) Reset() {
+	*x = QueryEventByBlockRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v2_spork_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryEventByBlockRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryEventByBlockRangeResponse) ProtoMessage() {}
+
+func (x *QueryEventByBlockRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_spork_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryEventByBlockRangeResponse.ProtoReflect.Descriptor instead.
+func (*QueryEventByBlockRangeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_spork_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueryEventByBlockRangeResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeResponse) GetFailedRanges() []*v1.FailedHeightRange {
+	if x != nil {
+		return x.FailedRanges
+	}
+	return nil
+}
+
+func (x *QueryEventByBlockRangeResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// StreamEventsResponse is one fetched batch of the block range [start, end], cursor is the height to resume from
+type StreamEventsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start  uint64   `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End    uint64   `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Cursor uint64   `protobuf:"varint,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Events []*Event `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *StreamEventsResponse) Reset() {
+	*x = StreamEventsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v2_spork_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsResponse) ProtoMessage() {}
+
+func (x *StreamEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_spork_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_spork_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamEventsResponse) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *StreamEventsResponse) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *StreamEventsResponse) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *StreamEventsResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Event replaces proto.v1.QueryEventByBlockRangeResponseEvent, whose blockId holds the block height
+// and whose timestamp is truncated to the second
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHeight uint64 `protobuf:"varint,1,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	// blockId is the hex encoded block ID
+	BlockId string `protobuf:"bytes,2,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	// timestamp is the block timestamp with its full precision
+	Timestamp        *timestamppb.Timestamp                    `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	EventId          string                                    `protobuf:"bytes,4,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	Index            int64                                     `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	Type             string                                    `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	TransactionId    string                                    `protobuf:"bytes,7,opt,name=transactionId,proto3" json:"transactionId,omitempty"`
+	TransactionIndex int64                                     `protobuf:"varint,8,opt,name=transactionIndex,proto3" json:"transactionIndex,omitempty"`
+	Values           []*v1.QueryEventByBlockRangeResponseValue `protobuf:"bytes,9,rep,name=values,proto3" json:"values,omitempty"`
+	// payload is the JSON-CDC encoded event, only set when includePayload is requested
+	Payload []byte `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	// payloadJson is the same JSON-CDC document as a string, JSON clients get it escaped and decode it once more
+	PayloadJson string `protobuf:"bytes,11,opt,name=payloadJson,proto3" json:"payloadJson,omitempty"`
+	// block and transaction are only set when enrich is requested
+	Block       *v1.BlockContext       `protobuf:"bytes,12,opt,name=block,proto3" json:"block,omitempty"`
+	Transaction *v1.TransactionContext `protobuf:"bytes,13,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_v2_spork_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_spork_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_proto_v2_spork_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Event) GetBlockHeight() uint64 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
+func (x *Event) GetBlockId() string {
+	if x != nil {
+		return x.BlockId
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Event) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *Event) GetTransactionIndex() int64 {
+	if x != nil {
+		return x.TransactionIndex
+	}
+	return 0
+}
+
+func (x *Event) GetValues() []*v1.QueryEventByBlockRangeResponseValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *Event) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *Event) GetBlock() *v1.BlockContext {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *Event) GetTransaction() *v1.TransactionContext {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+var File_proto_v2_spork_proto protoreflect.FileDescriptor
+
+var file_proto_v2_spork_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x32, 0x2f, 0x73, 0x70, 0x6f, 0x72, 0x6b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x32,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x70, 0x6f, 0x72,
+	0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xaa, 0x01, 0x0a, 0x1e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x48, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75,
+	0x72, 0x73, 0x6f, 0x72, 0x22, 0x7f, 0x0a, 0x14, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x03, 0x65, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x27, 0x0a, 0x06,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x84, 0x04, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x20, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12,
+	0x2a, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x45, 0x0a, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x20, 0x0a, 0x0b,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x2c,
+	0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3e, 0x0a, 0x0b,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52,
+	0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0x96, 0x03, 0x0a,
+	0x05, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x12, 0x6d, 0x0a, 0x16, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x32, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
+	0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x67, 0x0a, 0x18, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5b,
+	0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x41, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0f, 0x41,
+	0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x4f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76,
+	0x32, 0x42, 0x0a, 0x53, 0x70, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a,
+	0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x61, 0x74, 0x72,
+	0x69, 0x78, 0x4c, 0x61, 0x62, 0x73, 0x54, 0x65, 0x63, 0x68, 0x2f, 0x66, 0x6c, 0x6f, 0x77, 0x2d,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x2d, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_v2_spork_proto_rawDescOnce sync.Once
+	file_proto_v2_spork_proto_rawDescData = file_proto_v2_spork_proto_rawDesc
+)
+
+func file_proto_v2_spork_proto_rawDescGZIP() []byte {
+	file_proto_v2_spork_proto_rawDescOnce.Do(func() {
+		file_proto_v2_spork_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_v2_spork_proto_rawDescData)
+	})
+	return file_proto_v2_spork_proto_rawDescData
+}
+
+var file_proto_v2_spork_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_v2_spork_proto_goTypes = []interface{}{
+	(*QueryEventByBlockRangeResponse)(nil),         // 0: proto.v2.QueryEventByBlockRangeResponse
+	(*StreamEventsResponse)(nil),                   // 1: proto.v2.StreamEventsResponse
+	(*Event)(nil),                                  // 2: proto.v2.Event
+	(*v1.FailedHeightRange)(nil),                   // 3: proto.v1.FailedHeightRange
+	(*timestamppb.Timestamp)(nil),                  // 4: google.protobuf.Timestamp
+	(*v1.QueryEventByBlockRangeResponseValue)(nil), // 5: proto.v1.QueryEventByBlockRangeResponseValue
+	(*v1.BlockContext)(nil),                        // 6: proto.v1.BlockContext
+	(*v1.TransactionContext)(nil),                  // 7: proto.v1.TransactionContext
+	(*v1.QueryEventByBlockRangeRequest)(nil),       // 8: proto.v1.QueryEventByBlockRangeRequest
+	(*v1.QueryBlockAtTimeRequest)(nil),             // 9: proto.v1.QueryBlockAtTimeRequest
+	(*v1.AggregateEventsRequest)(nil),              // 10: proto.v1.AggregateEventsRequest
+	(*v1.QueryBlockAtTimeResponse)(nil),            // 11: proto.v1.QueryBlockAtTimeResponse
+	(*v1.AggregateEventsResponse)(nil),             // 12: proto.v1.AggregateEventsResponse
+}
+var file_proto_v2_spork_proto_depIdxs = []int32{
+	2,  // 0: proto.v2.QueryEventByBlockRangeResponse.events:type_name -> proto.v2.Event
+	3,  // 1: proto.v2.QueryEventByBlockRangeResponse.failedRanges:type_name -> proto.v1.FailedHeightRange
+	2,  // 2: proto.v2.StreamEventsResponse.events:type_name -> proto.v2.Event
+	4,  // 3: proto.v2.Event.timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 4: proto.v2.Event.values:type_name -> proto.v1.QueryEventByBlockRangeResponseValue
+	6,  // 5: proto.v2.Event.block:type_name -> proto.v1.BlockContext
+	7,  // 6: proto.v2.Event.transaction:type_name -> proto.v1.TransactionContext
+	8,  // 7: proto.v2.Spork.QueryEventByBlockRange:input_type -> proto.v1.QueryEventByBlockRangeRequest
+	8,  // 8: proto.v2.Spork.StreamEventsByBlockRange:input_type -> proto.v1.QueryEventByBlockRangeRequest
+	9,  // 9: proto.v2.Spork.QueryBlockAtTime:input_type -> proto.v1.QueryBlockAtTimeRequest
+	10, // 10: proto.v2.Spork.AggregateEvents:input_type -> proto.v1.AggregateEventsRequest
+	0,  // 11: proto.v2.Spork.QueryEventByBlockRange:output_type -> proto.v2.QueryEventByBlockRangeResponse
+	1,  // 12: proto.v2.Spork.StreamEventsByBlockRange:output_type -> proto.v2.StreamEventsResponse
+	11, // 13: proto.v2.Spork.QueryBlockAtTime:output_type -> proto.v1.QueryBlockAtTimeResponse
+	12, // 14: proto.v2.Spork.AggregateEvents:output_type -> proto.v1.AggregateEventsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_v2_spork_proto_init() }
+func file_proto_v2_spork_proto_init() {
+	if File_proto_v2_spork_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_v2_spork_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryEventByBlockRangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v2_spork_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamEventsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_v2_spork_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_v2_spork_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_v2_spork_proto_goTypes,
+		DependencyIndexes: file_proto_v2_spork_proto_depIdxs,
+		MessageInfos:      file_proto_v2_spork_proto_msgTypes,
+	}.Build()
+	File_proto_v2_spork_proto = out.File
+	file_proto_v2_spork_proto_rawDesc = nil
+	file_proto_v2_spork_proto_goTypes = nil
+	file_proto_v2_spork_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SporkClient is the client API for Spork service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SporkClient interface {
+	QueryEventByBlockRange(ctx context.Context, in *v1.QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (*QueryEventByBlockRangeResponse, error)
+	StreamEventsByBlockRange(ctx context.Context, in *v1.QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (Spork_StreamEventsByBlockRangeClient, error)
+	QueryBlockAtTime(ctx context.Context, in *v1.QueryBlockAtTimeRequest, opts ...grpc.CallOption) (*v1.QueryBlockAtTimeResponse, error)
+	AggregateEvents(ctx context.Context, in *v1.AggregateEventsRequest, opts ...grpc.CallOption) (*v1.AggregateEventsResponse, error)
+}
+
+type sporkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSporkClient(cc grpc.ClientConnInterface) SporkClient {
+	return &sporkClient{cc}
+}
+
+func (c *sporkClient) QueryEventByBlockRange(ctx context.Context, in *v1.QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (*QueryEventByBlockRangeResponse, error) {
+	out := new(QueryEventByBlockRangeResponse)
+	err := c.cc.Invoke(ctx, "/proto.v2.Spork/QueryEventByBlockRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporkClient) StreamEventsByBlockRange(ctx context.Context, in *v1.QueryEventByBlockRangeRequest, opts ...grpc.CallOption) (Spork_StreamEventsByBlockRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Spork_serviceDesc.Streams[0], "/proto.v2.Spork/StreamEventsByBlockRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sporkStreamEventsByBlockRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Spork_StreamEventsByBlockRangeClient interface {
+	Recv() (*StreamEventsResponse, error)
+	grpc.ClientStream
+}
+
+type sporkStreamEventsByBlockRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *sporkStreamEventsByBlockRangeClient) Recv() (*StreamEventsResponse, error) {
+	m := new(StreamEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sporkClient) QueryBlockAtTime(ctx context.Context, in *v1.QueryBlockAtTimeRequest, opts ...grpc.CallOption) (*v1.QueryBlockAtTimeResponse, error) {
+	out := new(v1.QueryBlockAtTimeResponse)
+	err := c.cc.Invoke(ctx, "/proto.v2.Spork/QueryBlockAtTime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporkClient) AggregateEvents(ctx context.Context, in *v1.AggregateEventsRequest, opts ...grpc.CallOption) (*v1.AggregateEventsResponse, error) {
+	out := new(v1.AggregateEventsResponse)
+	err := c.cc.Invoke(ctx, "/proto.v2.Spork/AggregateEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SporkServer is the server API for Spork service.
+type SporkServer interface {
+	QueryEventByBlockRange(context.Context, *v1.QueryEventByBlockRangeRequest) (*QueryEventByBlockRangeResponse, error)
+	StreamEventsByBlockRange(*v1.QueryEventByBlockRangeRequest, Spork_StreamEventsByBlockRangeServer) error
+	QueryBlockAtTime(context.Context, *v1.QueryBlockAtTimeRequest) (*v1.QueryBlockAtTimeResponse, error)
+	AggregateEvents(context.Context, *v1.AggregateEventsRequest) (*v1.AggregateEventsResponse, error)
+}
+
+// UnimplementedSporkServer can be embedded to have forward compatible implementations.
+type UnimplementedSporkServer struct {
+}
+
+func (*UnimplementedSporkServer) QueryEventByBlockRange(context.Context, *v1.QueryEventByBlockRangeRequest) (*QueryEventByBlockRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryEventByBlockRange not implemented")
+}
+func (*UnimplementedSporkServer) StreamEventsByBlockRange(*v1.QueryEventByBlockRangeRequest, Spork_StreamEventsByBlockRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEventsByBlockRange not implemented")
+}
+func (*UnimplementedSporkServer) QueryBlockAtTime(context.Context, *v1.QueryBlockAtTimeRequest) (*v1.QueryBlockAtTimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlockAtTime not implemented")
+}
+func (*UnimplementedSporkServer) AggregateEvents(context.Context, *v1.AggregateEventsRequest) (*v1.AggregateEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateEvents not implemented")
+}
+
+func RegisterSporkServer(s *grpc.Server, srv SporkServer) {
+	s.RegisterService(&_Spork_serviceDesc, srv)
+}
+
+func _Spork_QueryEventByBlockRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.QueryEventByBlockRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporkServer).QueryEventByBlockRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.v2.Spork/QueryEventByBlockRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporkServer).QueryEventByBlockRange(ctx, req.(*v1.QueryEventByBlockRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spork_StreamEventsByBlockRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(v1.QueryEventByBlockRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SporkServer).StreamEventsByBlockRange(m, &sporkStreamEventsByBlockRangeServer{stream})
+}
+
+type Spork_StreamEventsByBlockRangeServer interface {
+	Send(*StreamEventsResponse) error
+	grpc.ServerStream
+}
+
+type sporkStreamEventsByBlockRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *sporkStreamEventsByBlockRangeServer) Send(m *StreamEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Spork_QueryBlockAtTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.QueryBlockAtTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporkServer).QueryBlockAtTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.v2.Spork/QueryBlockAtTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporkServer).QueryBlockAtTime(ctx, req.(*v1.QueryBlockAtTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spork_AggregateEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.AggregateEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporkServer).AggregateEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.v2.Spork/AggregateEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporkServer).AggregateEvents(ctx, req.(*v1.AggregateEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Spork_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.v2.Spork",
+	HandlerType: (*SporkServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "QueryEventByBlockRange",
+			Handler:    _Spork_QueryEventByBlockRange_Handler,
+		},
+		{
+			MethodName: "QueryBlockAtTime",
+			Handler:    _Spork_QueryBlockAtTime_Handler,
+		},
+		{
+			MethodName: "AggregateEvents",
+			Handler:    _Spork_AggregateEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEventsByBlockRange",
+			Handler:       _Spork_StreamEventsByBlockRange_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/v2/spork.proto",
+}
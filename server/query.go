@@ -0,0 +1,274 @@
+/**
+ * server/query.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// ErrPagedPartial rejects partial mode together with pagination
+var ErrPagedPartial = errors.New("partial cannot be combined with limit or cursor")
+
+// ErrInvalidTimeRange rejects a time window that is incomplete, malformed or reversed
+var ErrInvalidTimeRange = errors.New("startTime and endTime must both be RFC 3339 timestamps with startTime before endTime")
+
+// ErrInvalidTimestamp rejects a timestamp that is not RFC 3339
+var ErrInvalidTimestamp = errors.New("timestamp must be an RFC 3339 timestamp")
+
+// ErrBlockNotSealed is returned when no block made at or after the requested time is sealed yet
+var ErrBlockNotSealed = errors.New("no sealed block at or after timestamp yet")
+
+// HasTimeRange reports whether req selects its blocks by time
+func HasTimeRange(req *pb.QueryEventByBlockRangeRequest) bool {
+	return req.StartTime != "" || req.EndTime != ""
+}
+
+// HeightRange returns the block range [start, end] of req, resolving startTime and endTime when they are set.
+// ok is false when no block falls in the time window.
+func HeightRange(ctx context.Context, flowClient spork.FlowClient, req *pb.QueryEventByBlockRangeRequest) (start uint64, end uint64, ok bool, err error) {
+	if !HasTimeRange(req) {
+		return req.Start, req.End, true, nil
+	}
+	startTime, err := time.Parse(time.RFC3339Nano, req.StartTime)
+	if err != nil {
+		return 0, 0, false, ErrInvalidTimeRange
+	}
+	endTime, err := time.Parse(time.RFC3339Nano, req.EndTime)
+	if err != nil || !startTime.Before(endTime) {
+		return 0, 0, false, ErrInvalidTimeRange
+	}
+
+	start, err = flowClient.QueryBlockHeightByTime(ctx, startTime)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	// the first block of endTime is the first one left out
+	next, err := flowClient.QueryBlockHeightByTime(ctx, endTime)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if next <= start {
+		return start, start, false, nil
+	}
+	return start, next - 1, true, nil
+}
+
+// IsPaged reports whether req asks for one page of events
+func IsPaged(req *pb.QueryEventByBlockRangeRequest) bool {
+	return req.Limit > 0 || req.Cursor != ""
+}
+
+// eventResult holds the events of a QueryEventByBlockRange request, ready to convert to any API version
+type eventResult struct {
+	blockEvents []client.BlockEvents
+
+	opts spork.EventJSONOptions
+
+	// partialErr lists the failed ranges in partial mode
+	partialErr error
+
+	nextCursor string
+}
+
+// queryBlockEvents runs a QueryEventByBlockRange request, a paged request only fetches the batches needed to fill its page
+func queryBlockEvents(ctx context.Context, flowClient spork.FlowClient, req *pb.QueryEventByBlockRangeRequest) (*eventResult, error) {
+	if IsPaged(req) && req.Partial {
+		return nil, ErrPagedPartial
+	}
+	filter, err := spork.NewEventFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	result := &eventResult{opts: spork.EventJSONOptions{IncludePayload: req.IncludePayload}}
+	start, end, ok, err := HeightRange(ctx, flowClient, req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return result, nil
+	}
+
+	if IsPaged(req) {
+		page, err := spork.QueryEventPage(ctx, flowClient, req.EventTypes(), filter, start, end, int(req.Limit), req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		result.blockEvents = page.BlockEvents
+		result.nextCursor = page.NextCursor
+	} else {
+		result.blockEvents, err = flowClient.QueryEventByBlockRange(spork.WithPartialResults(ctx, req.Partial), req.EventTypes(), start, end)
+		if err != nil && !spork.IsPartialError(err) {
+			return nil, err
+		}
+		result.partialErr = err
+		result.blockEvents = spork.FilterBlockEvents(result.blockEvents, filter)
+	}
+
+	if enricher := NewRequestEnricher(flowClient, req); enricher != nil {
+		if err := enricher.Fetch(ctx, result.blockEvents); err != nil {
+			return nil, err
+		}
+		result.opts.Enricher = enricher
+	}
+	return result, nil
+}
+
+// QueryEvents runs a QueryEventByBlockRange request, shared by the REST and gRPC APIs
+func QueryEvents(ctx context.Context, flowClient spork.FlowClient, req *pb.QueryEventByBlockRangeRequest) (*pb.QueryEventByBlockRangeResponse, error) {
+	result, err := queryBlockEvents(ctx, flowClient, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.QueryEventByBlockRangeResponse{
+		Events:       spork.BlockEventsToJSONWithOptions(result.blockEvents, result.opts),
+		FailedRanges: spork.FailedRangesToJSON(result.partialErr),
+		NextCursor:   result.nextCursor,
+	}, nil
+}
+
+// QueryEventsV2 is QueryEvents answering with v2 events
+func QueryEventsV2(ctx context.Context, flowClient spork.FlowClient, req *pb.QueryEventByBlockRangeRequest) (*pbv2.QueryEventByBlockRangeResponse, error) {
+	result, err := queryBlockEvents(ctx, flowClient, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.QueryEventByBlockRangeResponse{
+		Events:       spork.BlockEventsToV2(result.blockEvents, result.opts),
+		FailedRanges: spork.FailedRangesToJSON(result.partialErr),
+		NextCursor:   result.nextCursor,
+	}, nil
+}
+
+// AggregateEvents counts and sums up the events selected by req.Query batch by batch, shared by the REST and gRPC APIs
+func AggregateEvents(ctx context.Context, flowClient spork.FlowClient, req *pb.AggregateEventsRequest) (*pb.AggregateEventsResponse, error) {
+	bucket, err := ValidateAggregate(req)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := spork.NewEventFilter(req.Query.Filter)
+	if err != nil {
+		return nil, err
+	}
+	aggregator := spork.NewEventAggregator(req.Field, req.GroupBy, bucket)
+	start, end, ok, err := HeightRange(ctx, flowClient, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// only the running aggregates are kept, not the events
+		err = flowClient.StreamEventByBlockRange(ctx, req.Query.EventTypes(), start, end, func(_ uint64, _ uint64, blockEvents []client.BlockEvents) error {
+			return aggregator.Add(spork.FilterBlockEvents(blockEvents, filter))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &pb.AggregateEventsResponse{Groups: aggregator.Results()}, nil
+}
+
+// NewRequestEnricher returns the enricher of one request, nil when req does not ask for enrichment
+func NewRequestEnricher(flowClient spork.FlowClient, req *pb.QueryEventByBlockRangeRequest) *spork.Enricher {
+	if !req.Enrich {
+		return nil
+	}
+	return spork.NewEnricher(flowClient)
+}
+
+// EventsToJSON converts the blockEvents matching the filter of req as req asks,
+// fetching their blocks and transactions first when enricher is set
+func EventsToJSON(ctx context.Context, enricher *spork.Enricher, req *pb.QueryEventByBlockRangeRequest, blockEvents []client.BlockEvents) ([]*pb.QueryEventByBlockRangeResponseEvent, error) {
+	blockEvents, opts, err := prepareEvents(ctx, enricher, req, blockEvents)
+	if err != nil {
+		return nil, err
+	}
+	return spork.BlockEventsToJSONWithOptions(blockEvents, opts), nil
+}
+
+// EventsToV2 is EventsToJSON converting to v2 events
+func EventsToV2(ctx context.Context, enricher *spork.Enricher, req *pb.QueryEventByBlockRangeRequest, blockEvents []client.BlockEvents) ([]*pbv2.Event, error) {
+	blockEvents, opts, err := prepareEvents(ctx, enricher, req, blockEvents)
+	if err != nil {
+		return nil, err
+	}
+	return spork.BlockEventsToV2(blockEvents, opts), nil
+}
+
+// prepareEvents filters blockEvents before the enricher fetches anything for them
+func prepareEvents(ctx context.Context, enricher *spork.Enricher, req *pb.QueryEventByBlockRangeRequest, blockEvents []client.BlockEvents) ([]client.BlockEvents, spork.EventJSONOptions, error) {
+	opts := spork.EventJSONOptions{IncludePayload: req.IncludePayload}
+	filter, err := spork.NewEventFilter(req.Filter)
+	if err != nil {
+		return nil, opts, err
+	}
+	blockEvents = spork.FilterBlockEvents(blockEvents, filter)
+	if enricher != nil {
+		if err := enricher.Fetch(ctx, blockEvents); err != nil {
+			return nil, opts, err
+		}
+		opts.Enricher = enricher
+	}
+	return blockEvents, opts, nil
+}
+
+// BlockAtTime returns the first sealed block made at or after req.Timestamp, shared by the REST and gRPC APIs
+func BlockAtTime(ctx context.Context, flowClient spork.FlowClient, req *pb.QueryBlockAtTimeRequest) (*pb.QueryBlockAtTimeResponse, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, req.Timestamp)
+	if err != nil {
+		return nil, ErrInvalidTimestamp
+	}
+
+	height, err := flowClient.QueryBlockHeightByTime(ctx, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	// access nodes also serve finalized blocks past the sealed head
+	latestHeight, err := flowClient.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > latestHeight {
+		return nil, ErrBlockNotSealed
+	}
+
+	header, err := flowClient.QueryBlockHeaderByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.QueryBlockAtTimeResponse{
+		BlockHeight: header.Height,
+		BlockId:     header.ID.String(),
+		Timestamp:   timestamppb.New(header.Timestamp),
+	}, nil
+}
+
+// isInvalidQuery reports whether err comes from the request itself rather than from fetching
+func isInvalidQuery(err error) bool {
+	var validationErr *ValidationError
+	return errors.Is(err, ErrPagedPartial) || errors.Is(err, spork.ErrInvalidCursor) || errors.Is(err, ErrInvalidTimeRange) ||
+		errors.Is(err, ErrInvalidTimestamp) || errors.Is(err, spork.ErrRangeTooLarge) || errors.Is(err, spork.ErrInvalidFilter) ||
+		errors.Is(err, spork.ErrTooManyGroups) || errors.As(err, &validationErr)
+}
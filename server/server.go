@@ -0,0 +1,224 @@
+/**
+ * server/server.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// Version is the service version reported by both the REST and gRPC APIs
+const Version = "1.0.0"
+
+// SporkServer implements the gRPC Spork service on top of a spork.FlowClient
+type SporkServer struct {
+	pb.UnimplementedSporkServer
+
+	flowClient spork.FlowClient
+
+	hub *spork.SubscriptionHub
+
+	backendMode string
+}
+
+// NewSporkServer creates the service, subscriptions are disabled if hub is nil
+func NewSporkServer(flowClient spork.FlowClient, hub *spork.SubscriptionHub, backendMode string) *SporkServer {
+	return &SporkServer{flowClient: flowClient, hub: hub, backendMode: backendMode}
+}
+
+// NewGRPCServer creates a grpc server with the v1 and v2 Spork services registered
+func NewGRPCServer(flowClient spork.FlowClient, hub *spork.SubscriptionHub, backendMode string, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterSporkServer(grpcServer, NewSporkServer(flowClient, hub, backendMode))
+	pbv2.RegisterSporkServer(grpcServer, NewSporkServerV2(flowClient))
+	return grpcServer
+}
+
+func (s *SporkServer) Version(ctx context.Context, req *pb.VersionRequest) (*pb.VersionResponse, error) {
+	return &pb.VersionResponse{
+		Version:     Version,
+		BackendMode: s.backendMode,
+	}, nil
+}
+
+func (s *SporkServer) SyncSpork(ctx context.Context, req *pb.SyncSporkRequest) (*pb.SyncSporkResponse, error) {
+	err := s.flowClient.SyncSpork()
+	if err != nil {
+		log.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.SyncSporkResponse{Spork: s.flowClient.String()}, nil
+}
+
+func (s *SporkServer) QueryLatestBlockHeight(ctx context.Context, req *pb.QueryLatestBlockHeightRequest) (*pb.QueryLatestBlockHeightResponse, error) {
+	height, err := s.flowClient.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, statusError(err)
+	}
+	return &pb.QueryLatestBlockHeightResponse{LatestBlockHeight: height}, nil
+}
+
+func (s *SporkServer) QueryEventByBlockRange(ctx context.Context, req *pb.QueryEventByBlockRangeRequest) (*pb.QueryEventByBlockRangeResponse, error) {
+	eventTypes := req.EventTypes()
+	if len(eventTypes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one event type is required")
+	}
+	log.Info(fmt.Sprintf("grpc query %v, from %d to %d", eventTypes, req.Start, req.End))
+
+	resp, err := QueryEvents(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+
+	log.Info(fmt.Sprintf("Got %d events", len(resp.Events)))
+	return resp, nil
+}
+
+func (s *SporkServer) StreamEventsByBlockRange(req *pb.QueryEventByBlockRangeRequest, stream pb.Spork_StreamEventsByBlockRangeServer) error {
+	eventTypes := req.EventTypes()
+	if len(eventTypes) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one event type is required")
+	}
+	if _, err := spork.NewEventFilter(req.Filter); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	start, end, ok, err := HeightRange(stream.Context(), s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return queryStatusError(err)
+	}
+	if !ok {
+		return nil
+	}
+	log.Info(fmt.Sprintf("grpc stream %v, from %d to %d", eventTypes, start, end))
+
+	enricher := NewRequestEnricher(s.flowClient, req)
+	err = s.flowClient.StreamEventByBlockRange(stream.Context(), eventTypes, start, end, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		// stop fetching once the client is gone
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+		events, err := EventsToJSON(stream.Context(), enricher, req, blockEvents)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&pb.StreamEventsByBlockRangeResponse{
+			Start:  start,
+			End:    end,
+			Cursor: end + 1,
+			Events: events,
+		})
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *SporkServer) QueryBlockAtTime(ctx context.Context, req *pb.QueryBlockAtTimeRequest) (*pb.QueryBlockAtTimeResponse, error) {
+	resp, err := BlockAtTime(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+	return resp, nil
+}
+
+func (s *SporkServer) AggregateEvents(ctx context.Context, req *pb.AggregateEventsRequest) (*pb.AggregateEventsResponse, error) {
+	resp, err := AggregateEvents(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+	return resp, nil
+}
+
+func (s *SporkServer) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.Spork_SubscribeEventsServer) error {
+	if s.hub == nil {
+		return status.Error(codes.Unimplemented, "subscriptions are disabled")
+	}
+	for _, eventType := range req.Events {
+		if err := ValidateEventType(eventType); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	sub, err := s.hub.Subscribe(req.Events, req.Start)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case chunk, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "subscription closed")
+			}
+			if err := stream.Send(spork.SubscriptionChunkToJSON(chunk)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// queryStatusError reports the errors of the request itself as invalid arguments, see statusError for the others
+func queryStatusError(err error) error {
+	if isInvalidQuery(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrBlockNotSealed) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return statusError(err)
+}
+
+// StatusCode returns the gRPC code the APIs report err with
+func StatusCode(err error) codes.Code {
+	return status.Code(queryStatusError(err))
+}
+
+// statusError keeps the code of gRPC errors and of expired or cancelled contexts, anything else is internal
+func statusError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
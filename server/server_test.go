@@ -0,0 +1,732 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+const (
+	testEventSignature          = "A.1654653399040a61.FlowToken.TokensDeposited"
+	testWithdrawnEventSignature = "A.1654653399040a61.FlowToken.TokensWithdrawn"
+	fakeChunkSize               = 10
+)
+
+// fakeFlowClient is an in-memory FlowClient serving canned block events
+type fakeFlowClient struct {
+	blockEvents  []client.BlockEvents
+	latestHeight uint64
+	err          error
+	syncCount    int
+
+	// block holds every query until its context is done, then closes released
+	block    bool
+	released chan struct{}
+}
+
+func (f *fakeFlowClient) String() string {
+	return "fakeFlowClient"
+}
+
+func (f *fakeFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	if f.block {
+		<-ctx.Done()
+		close(f.released)
+		return nil, ctx.Err()
+	}
+	// a partial error comes with the events
+	if f.err != nil && !spork.IsPartialError(f.err) {
+		return nil, f.err
+	}
+	result := make([]client.BlockEvents, 0)
+	for _, blockEvent := range f.blockEvents {
+		if blockEvent.Height < start || blockEvent.Height > end {
+			continue
+		}
+		events := make([]flow.Event, 0)
+		for _, event := range blockEvent.Events {
+			for _, eventType := range eventTypes {
+				if event.Type == eventType {
+					events = append(events, event)
+				}
+			}
+		}
+		result = append(result, client.BlockEvents{
+			BlockID:        blockEvent.BlockID,
+			Height:         blockEvent.Height,
+			BlockTimestamp: blockEvent.BlockTimestamp,
+			Events:         events,
+		})
+	}
+	return result, f.err
+}
+
+// StreamEventByBlockRange hands out the canned events in chunks of fakeChunkSize blocks
+func (f *fakeFlowClient) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler spork.BlockEventsHandler) error {
+	for i := start; i <= end; i += fakeChunkSize {
+		chunkEnd := i + fakeChunkSize - 1
+		if chunkEnd > end {
+			chunkEnd = end
+		}
+		ret, err := f.QueryEventByBlockRange(ctx, eventTypes, i, chunkEnd)
+		if err != nil {
+			return err
+		}
+		if err := handler(i, chunkEnd, ret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeFlowClient) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.latestHeight, nil
+}
+
+// QueryBlockHeightByTime returns the first canned block not older than timestamp
+func (f *fakeFlowClient) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	for _, blockEvent := range f.blockEvents {
+		if !blockEvent.BlockTimestamp.Before(timestamp) {
+			return blockEvent.Height, nil
+		}
+	}
+	return f.latestHeight + 1, nil
+}
+
+func (f *fakeFlowClient) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	for _, blockEvent := range f.blockEvents {
+		if blockEvent.Height == height {
+			return &flow.BlockHeader{ID: blockEvent.BlockID, Height: height, Timestamp: blockEvent.BlockTimestamp}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "block %d not found", height)
+}
+
+// QueryTransaction returns a transaction paid by 0x01 and authorized by 0x02
+func (f *fakeFlowClient) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*spork.TransactionInfo, error) {
+	return &spork.TransactionInfo{
+		Payer:       flow.HexToAddress("01"),
+		Proposer:    flow.HexToAddress("01"),
+		Authorizers: []flow.Address{flow.HexToAddress("02")},
+		Status:      flow.TransactionStatusSealed,
+	}, nil
+}
+
+func (f *fakeFlowClient) SyncSpork() error {
+	f.syncCount++
+	return f.err
+}
+
+func (f *fakeFlowClient) Close() error {
+	return nil
+}
+
+func newTestBlockEvents(height uint64) client.BlockEvents {
+	eventType := &cadence.EventType{
+		QualifiedIdentifier: "FlowToken.TokensDeposited",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+		},
+	}
+	amount, _ := cadence.NewUFix64("1.5")
+	value := cadence.NewEvent([]cadence.Value{amount}).WithType(eventType)
+	payload, _ := jsoncdc.Encode(value)
+	return client.BlockEvents{
+		BlockID:        flow.HexToID("01"),
+		Height:         height,
+		BlockTimestamp: time.Unix(1640000000, 0),
+		Events: []flow.Event{{
+			Type:          testEventSignature,
+			TransactionID: flow.HexToID("02"),
+			Value:         value,
+			Payload:       payload,
+		}},
+	}
+}
+
+// newBufconnClient serves the Spork service in-process and returns a client connected to it
+func newBufconnClient(t *testing.T, flowClient *fakeFlowClient) pb.SporkClient {
+	return pb.NewSporkClient(newBufconnConn(t, flowClient))
+}
+
+// newBufconnConn serves the v1 and v2 services over flowClient in-process
+func newBufconnConn(t *testing.T, flowClient *fakeFlowClient) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	hub := spork.NewSubscriptionHub(flowClient, 5*time.Millisecond, fakeChunkSize)
+	hub.Start()
+	t.Cleanup(func() { hub.Close() })
+
+	grpcServer := NewGRPCServer(flowClient, hub, "fake")
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure())
+	require.Nil(t, err, "err should be nil for bufconn dial")
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestGRPCVersion(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{})
+
+	resp, err := sporkClient.Version(context.Background(), &pb.VersionRequest{})
+	require.Nil(t, err)
+	require.Equal(t, Version, resp.Version)
+	require.Equal(t, "fake", resp.BackendMode)
+}
+
+func TestGRPCQueryLatestBlockHeight(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{latestHeight: 21291000})
+
+	resp, err := sporkClient.QueryLatestBlockHeight(context.Background(), &pb.QueryLatestBlockHeightRequest{})
+	require.Nil(t, err)
+	require.Equal(t, uint64(21291000), resp.LatestBlockHeight)
+}
+
+func TestGRPCSyncSpork(t *testing.T) {
+	flowClient := &fakeFlowClient{}
+	sporkClient := newBufconnClient(t, flowClient)
+
+	resp, err := sporkClient.SyncSpork(context.Background(), &pb.SyncSporkRequest{})
+	require.Nil(t, err)
+	require.Equal(t, "fakeFlowClient", resp.Spork)
+	require.Equal(t, 1, flowClient.syncCount)
+}
+
+func TestGRPCQueryEventByBlockRange(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(200)},
+	})
+
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   150,
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	require.Equal(t, testEventSignature, resp.Events[0].Type)
+	require.Equal(t, "amount", resp.Events[0].Values[0].Name)
+	require.Equal(t, "1.50000000", resp.Events[0].Values[0].Value)
+}
+
+func TestGRPCQueryEventByBlockRangeIncludePayload(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100)},
+	})
+
+	req := &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 150}
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Empty(t, resp.Events[0].Payload)
+	require.Empty(t, resp.Events[0].PayloadJson)
+
+	req.IncludePayload = true
+	resp, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, string(resp.Events[0].Payload), resp.Events[0].PayloadJson)
+	require.JSONEq(t,
+		`{"type":"Event","value":{"id":"FlowToken.TokensDeposited","fields":[{"name":"amount","value":{"type":"UFix64","value":"1.50000000"}}]}}`,
+		resp.Events[0].PayloadJson)
+
+	// the REST API encodes payloadJson as an escaped string holding the JSON-CDC document
+	body, err := json.Marshal(resp.Events[0])
+	require.Nil(t, err)
+	var restEvent struct {
+		PayloadJson string `json:"payloadJson"`
+	}
+	require.Nil(t, json.Unmarshal(body, &restEvent))
+	require.Equal(t, resp.Events[0].PayloadJson, restEvent.PayloadJson)
+}
+
+func TestGRPCQueryEventByBlockRangeError(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{err: errors.New("access node unavailable")})
+
+	_, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   150,
+	})
+	require.NotNil(t, err)
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestGRPCQueryEventByBlockRangeDeadline(t *testing.T) {
+	flowClient := &fakeFlowClient{block: true, released: make(chan struct{})}
+	sporkClient := newBufconnClient(t, flowClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := sporkClient.QueryEventByBlockRange(ctx, &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   150,
+	})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// the deadline of the client reaches the FlowClient
+	select {
+	case <-flowClient.released:
+	case <-time.After(time.Second):
+		t.Fatal("query was not cancelled")
+	}
+}
+
+func TestGRPCQueryEventByBlockRangePartial(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(140)},
+		err: &spork.PartialError{FailedRanges: []spork.FailedRange{
+			{Start: 120, End: 129, Err: errors.New("access node unavailable")},
+		}},
+	})
+
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:   testEventSignature,
+		Start:   100,
+		End:     150,
+		Partial: true,
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	require.Len(t, resp.FailedRanges, 1)
+	require.Equal(t, uint64(120), resp.FailedRanges[0].Start)
+	require.Equal(t, uint64(129), resp.FailedRanges[0].End)
+	require.Equal(t, "access node unavailable", resp.FailedRanges[0].Error)
+}
+
+func TestGRPCQueryEventByBlockRangePaged(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(125)},
+	})
+
+	req := &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 150, Limit: 2}
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	require.NotEmpty(t, resp.NextCursor)
+
+	req.Cursor = resp.NextCursor
+	resp, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, uint64(125), resp.Events[0].BlockId)
+	require.Empty(t, resp.NextCursor)
+
+	req.Cursor = "garbage"
+	_, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req.Cursor = ""
+	req.Partial = true
+	_, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCQueryEventByBlockRangeTimeWindow(t *testing.T) {
+	blockEvents := []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(125)}
+	for i := range blockEvents {
+		blockEvents[i].BlockTimestamp = time.Unix(1640000000+int64(blockEvents[i].Height), 0)
+	}
+	sporkClient := newBufconnClient(t, &fakeFlowClient{blockEvents: blockEvents, latestHeight: 130})
+
+	// the window ends before the block made at endTime
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:     testEventSignature,
+		StartTime: time.Unix(1640000100, 0).UTC().Format(time.RFC3339),
+		EndTime:   time.Unix(1640000125, 0).UTC().Format(time.RFC3339),
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	require.Equal(t, uint64(100), resp.Events[0].BlockId)
+	require.Equal(t, uint64(105), resp.Events[1].BlockId)
+
+	resp, err = sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:     testEventSignature,
+		StartTime: time.Unix(1640000106, 0).UTC().Format(time.RFC3339),
+		EndTime:   time.Unix(1640000110, 0).UTC().Format(time.RFC3339),
+	})
+	require.Nil(t, err)
+	require.Empty(t, resp.Events)
+
+	for _, req := range []*pb.QueryEventByBlockRangeRequest{
+		{Event: testEventSignature, StartTime: "2022-03-01T00:00:00Z"},
+		{Event: testEventSignature, StartTime: "yesterday", EndTime: "2022-03-01T00:00:00Z"},
+		{Event: testEventSignature, StartTime: "2022-03-02T00:00:00Z", EndTime: "2022-03-01T00:00:00Z"},
+	} {
+		_, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGRPCQueryBlockAtTime(t *testing.T) {
+	blockEvents := []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(125)}
+	for i := range blockEvents {
+		blockEvents[i].BlockID = flow.BytesToID([]byte{byte(blockEvents[i].Height)})
+		blockEvents[i].BlockTimestamp = time.Unix(1640000000+int64(blockEvents[i].Height), 0)
+	}
+	sporkClient := newBufconnClient(t, &fakeFlowClient{blockEvents: blockEvents, latestHeight: 125})
+
+	resp, err := sporkClient.QueryBlockAtTime(context.Background(), &pb.QueryBlockAtTimeRequest{
+		Timestamp: time.Unix(1640000101, 0).UTC().Format(time.RFC3339),
+	})
+	require.Nil(t, err)
+	require.Equal(t, uint64(105), resp.BlockHeight)
+	require.Equal(t, blockEvents[1].BlockID.String(), resp.BlockId)
+	require.Equal(t, int64(1640000105), resp.Timestamp.Seconds)
+
+	_, err = sporkClient.QueryBlockAtTime(context.Background(), &pb.QueryBlockAtTimeRequest{
+		Timestamp: time.Unix(1640000126, 0).UTC().Format(time.RFC3339),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = sporkClient.QueryBlockAtTime(context.Background(), &pb.QueryBlockAtTimeRequest{Timestamp: "yesterday"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCQueryEventByBlockRangeEnrich(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105)},
+	})
+
+	req := &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 110}
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Nil(t, resp.Events[0].Block)
+	require.Nil(t, resp.Events[0].Transaction)
+
+	req.Enrich = true
+	resp, err = sporkClient.QueryEventByBlockRange(context.Background(), req)
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	for _, event := range resp.Events {
+		require.Equal(t, flow.HexToID("01").String(), event.Block.Id)
+		require.Equal(t, "0x0000000000000001", event.Transaction.Payer)
+		require.Equal(t, []string{"0x0000000000000002"}, event.Transaction.Authorizers)
+		require.Equal(t, "SEALED", event.Transaction.Status)
+	}
+}
+
+func TestGRPCV2QueryEventByBlockRange(t *testing.T) {
+	blockEvent := newTestBlockEvents(100)
+	blockEvent.BlockID = flow.HexToID("0a0b")
+	blockEvent.BlockTimestamp = time.Unix(1640000000, 250000000)
+	sporkClient := pbv2.NewSporkClient(newBufconnConn(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{blockEvent, newTestBlockEvents(105)},
+	}))
+
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   110,
+		Limit: 1,
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, uint64(100), resp.Events[0].BlockHeight)
+	require.Equal(t, flow.HexToID("0a0b").String(), resp.Events[0].BlockId)
+	require.Equal(t, int32(250000000), resp.Events[0].Timestamp.Nanos)
+	require.Equal(t, "1.50000000", resp.Events[0].Values[0].Value)
+	require.NotEmpty(t, resp.NextCursor)
+
+	_, err = sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{Start: 100, End: 110})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCQueryEventByBlockRangeFilter(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105)},
+	})
+
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:  testEventSignature,
+		Start:  100,
+		End:    110,
+		Filter: &pb.EventFilter{Field: "amount", Gte: "2"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 0)
+
+	resp, err = sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:  testEventSignature,
+		Start:  100,
+		End:    110,
+		Limit:  1,
+		Filter: &pb.EventFilter{Or: []*pb.EventFilter{{Field: "amount", Eq: "1.5"}, {Field: "amount", Eq: "3"}}},
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 1)
+	require.NotEmpty(t, resp.NextCursor)
+
+	_, err = sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event:  testEventSignature,
+		Start:  100,
+		End:    110,
+		Filter: &pb.EventFilter{Field: "amount"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCAggregateEvents(t *testing.T) {
+	later := newTestBlockEvents(125)
+	later.BlockTimestamp = later.BlockTimestamp.Add(2 * time.Hour)
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), later},
+	})
+
+	resp, err := sporkClient.AggregateEvents(context.Background(), &pb.AggregateEventsRequest{
+		Query:  &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 125},
+		Field:  "amount",
+		Bucket: "1h",
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Groups, 2)
+	require.Equal(t, uint64(2), resp.Groups[0].Count)
+	require.Equal(t, "3.00000000", resp.Groups[0].Sum)
+	require.Equal(t, "1.50000000", resp.Groups[0].Max)
+	require.Equal(t, time.Unix(1640000000, 0).Truncate(time.Hour).Unix(), resp.Groups[0].BucketStart.Seconds)
+	require.Equal(t, uint64(1), resp.Groups[1].Count)
+
+	for _, req := range []*pb.AggregateEventsRequest{
+		{Field: "amount"},
+		{Query: &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 125, Limit: 10}},
+		{Query: &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 125}, Bucket: "hourly"},
+	} {
+		_, err := sporkClient.AggregateEvents(context.Background(), req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	require.Nil(t, ValidateQuery(&pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 110}))
+	require.Nil(t, ValidateQuery(&pb.QueryEventByBlockRangeRequest{Event: "flow.AccountCreated", Start: 100, End: 100}))
+	require.Nil(t, ValidateQuery(&pb.QueryEventByBlockRangeRequest{
+		Event:     testEventSignature,
+		StartTime: "2022-01-01T00:00:00Z",
+		EndTime:   "2022-01-02T00:00:00Z",
+	}))
+
+	invalid := map[string]*pb.QueryEventByBlockRangeRequest{
+		"events":    {Start: 100, End: 110},
+		"start":     {Event: testEventSignature, Start: 100, StartTime: "2022-01-01T00:00:00Z", EndTime: "2022-01-02T00:00:00Z"},
+		"startTime": {Event: testEventSignature, StartTime: "2022-01-01T00:00:00Z"},
+		"end":       {Event: testEventSignature, Start: 110, End: 100},
+		"partial":   {Event: testEventSignature, Start: 100, End: 110, Partial: true, Limit: 10},
+		"filter":    {Event: testEventSignature, Start: 100, End: 110, Filter: &pb.EventFilter{Eq: "1"}},
+	}
+	for field, req := range invalid {
+		err := ValidateQuery(req)
+		require.NotNil(t, err, field)
+		require.Equal(t, field, InvalidField(err))
+		require.Equal(t, codes.InvalidArgument, StatusCode(err))
+	}
+
+	for _, eventType := range []string{"FlowToken.TokensDeposited", "A.1654653399040a61.FlowToken", "A.xyz.FlowToken.TokensDeposited", "flow.Account Created"} {
+		err := ValidateQuery(&pb.QueryEventByBlockRangeRequest{Event: eventType, Start: 100, End: 110})
+		require.Equal(t, "events", InvalidField(err), eventType)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	require.Equal(t, codes.InvalidArgument, StatusCode(spork.ErrInvalidCursor))
+	require.Equal(t, "cursor", InvalidField(spork.ErrInvalidCursor))
+	require.Equal(t, codes.InvalidArgument, StatusCode(spork.ErrRangeTooLarge))
+	require.Equal(t, codes.NotFound, StatusCode(ErrBlockNotSealed))
+	require.Equal(t, codes.DeadlineExceeded, StatusCode(context.DeadlineExceeded))
+	require.Equal(t, codes.Unavailable, StatusCode(status.Error(codes.Unavailable, "down")))
+	require.Equal(t, codes.Internal, StatusCode(errors.New("boom")))
+	require.Equal(t, "", InvalidField(errors.New("boom")))
+}
+
+func TestGRPCV2QueryEventByBlockRangeInvalid(t *testing.T) {
+	fc := &fakeFlowClient{blockEvents: []client.BlockEvents{newTestBlockEvents(100)}}
+	sporkClient := pbv2.NewSporkClient(newBufconnConn(t, fc))
+
+	for _, req := range []*pb.QueryEventByBlockRangeRequest{
+		{Event: "FlowToken.TokensDeposited", Start: 100, End: 110},
+		{Event: testEventSignature, Start: 110, End: 100},
+		{Event: testEventSignature, Start: 100, End: 110, StartTime: "2022-01-01T00:00:00Z", EndTime: "2022-01-02T00:00:00Z"},
+	} {
+		_, err := sporkClient.QueryEventByBlockRange(context.Background(), req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGRPCV2StreamEventsByBlockRange(t *testing.T) {
+	blockEvent := newTestBlockEvents(100)
+	blockEvent.BlockID = flow.HexToID("0a0b")
+	sporkClient := pbv2.NewSporkClient(newBufconnConn(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{blockEvent, newTestBlockEvents(125)},
+	}))
+
+	stream, err := sporkClient.StreamEventsByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   125,
+	})
+	require.Nil(t, err)
+	chunks := make([]*pbv2.StreamEventsResponse, 0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		chunks = append(chunks, chunk)
+	}
+	require.Len(t, chunks, 3)
+	require.Equal(t, uint64(110), chunks[0].Cursor)
+	require.Len(t, chunks[0].Events, 1)
+	require.Equal(t, flow.HexToID("0a0b").String(), chunks[0].Events[0].BlockId)
+	require.Len(t, chunks[2].Events, 1)
+
+	stream, err = sporkClient.StreamEventsByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 110, End: 100})
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCV2QueryBlockAtTime(t *testing.T) {
+	blockEvents := []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105)}
+	for i := range blockEvents {
+		blockEvents[i].BlockTimestamp = time.Unix(1640000000+int64(blockEvents[i].Height), 0)
+	}
+	sporkClient := pbv2.NewSporkClient(newBufconnConn(t, &fakeFlowClient{blockEvents: blockEvents, latestHeight: 105}))
+
+	block, err := sporkClient.QueryBlockAtTime(context.Background(), &pb.QueryBlockAtTimeRequest{
+		Timestamp: time.Unix(1640000101, 0).UTC().Format(time.RFC3339),
+	})
+	require.Nil(t, err)
+	require.Equal(t, uint64(105), block.BlockHeight)
+	_, err = sporkClient.QueryBlockAtTime(context.Background(), &pb.QueryBlockAtTimeRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCV2AggregateEvents(t *testing.T) {
+	sporkClient := pbv2.NewSporkClient(newBufconnConn(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105)},
+	}))
+
+	aggregate, err := sporkClient.AggregateEvents(context.Background(), &pb.AggregateEventsRequest{
+		Query: &pb.QueryEventByBlockRangeRequest{Event: testEventSignature, Start: 100, End: 105},
+		Field: "amount",
+	})
+	require.Nil(t, err)
+	require.Len(t, aggregate.Groups, 1)
+	require.Equal(t, uint64(2), aggregate.Groups[0].Count)
+	_, err = sporkClient.AggregateEvents(context.Background(), &pb.AggregateEventsRequest{
+		Query: &pb.QueryEventByBlockRangeRequest{Event: "FlowToken.TokensDeposited", Start: 100, End: 105},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCStreamEventsByBlockRange(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(125)},
+	})
+
+	stream, err := sporkClient.StreamEventsByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Event: testEventSignature,
+		Start: 100,
+		End:   125,
+	})
+	require.Nil(t, err)
+
+	chunks := make([]*pb.StreamEventsByBlockRangeResponse, 0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	require.Len(t, chunks, 3)
+	require.Equal(t, uint64(100), chunks[0].Start)
+	require.Equal(t, uint64(109), chunks[0].End)
+	require.Equal(t, uint64(110), chunks[0].Cursor)
+	require.Len(t, chunks[0].Events, 2)
+	require.Len(t, chunks[1].Events, 0)
+	require.Equal(t, uint64(120), chunks[2].Start)
+	require.Equal(t, uint64(125), chunks[2].End)
+	require.Len(t, chunks[2].Events, 1)
+}
+
+func TestGRPCSubscribeEvents(t *testing.T) {
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		latestHeight: 125,
+		blockEvents:  []client.BlockEvents{newTestBlockEvents(100), newTestBlockEvents(105), newTestBlockEvents(125)},
+	})
+
+	stream, err := sporkClient.SubscribeEvents(context.Background(), &pb.SubscribeEventsRequest{
+		Events: []string{testEventSignature},
+		Start:  100,
+	})
+	require.Nil(t, err)
+
+	received := 0
+	for received < 3 {
+		chunk, err := stream.Recv()
+		require.Nil(t, err)
+		require.LessOrEqual(t, chunk.End, uint64(125))
+		received += len(chunk.Events)
+	}
+	require.Equal(t, 3, received)
+
+	stream, err = sporkClient.SubscribeEvents(context.Background(), &pb.SubscribeEventsRequest{
+		Events: []string{"FlowToken.TokensDeposited"},
+	})
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCQueryEventByBlockRangeMultipleEvents(t *testing.T) {
+	withdrawn := newTestBlockEvents(105)
+	withdrawn.Events[0].Type = testWithdrawnEventSignature
+	withdrawn.Events[0].EventIndex = 1
+	sporkClient := newBufconnClient(t, &fakeFlowClient{
+		blockEvents: []client.BlockEvents{newTestBlockEvents(100), withdrawn},
+	})
+
+	resp, err := sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{
+		Events: []string{testEventSignature, testWithdrawnEventSignature},
+		Start:  100,
+		End:    150,
+	})
+	require.Nil(t, err)
+	require.Len(t, resp.Events, 2)
+	require.Equal(t, testEventSignature, resp.Events[0].Type)
+	require.Equal(t, testWithdrawnEventSignature, resp.Events[1].Type)
+
+	_, err = sporkClient.QueryEventByBlockRange(context.Background(), &pb.QueryEventByBlockRangeRequest{Start: 100, End: 150})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
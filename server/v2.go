@@ -0,0 +1,122 @@
+/**
+ * server/v2.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// SporkServerV2 implements the gRPC v2 Spork service, served next to the v1 one.
+// Unlike v1, every request is checked by ValidateQuery first.
+type SporkServerV2 struct {
+	pbv2.UnimplementedSporkServer
+
+	flowClient spork.FlowClient
+}
+
+func NewSporkServerV2(flowClient spork.FlowClient) *SporkServerV2 {
+	return &SporkServerV2{flowClient: flowClient}
+}
+
+func (s *SporkServerV2) QueryEventByBlockRange(ctx context.Context, req *pb.QueryEventByBlockRangeRequest) (*pbv2.QueryEventByBlockRangeResponse, error) {
+	if err := ValidateQuery(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	log.Info(fmt.Sprintf("grpc v2 query %v, from %d to %d", req.EventTypes(), req.Start, req.End))
+
+	resp, err := QueryEventsV2(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+
+	log.Info(fmt.Sprintf("Got %d events", len(resp.Events)))
+	return resp, nil
+}
+
+func (s *SporkServerV2) StreamEventsByBlockRange(req *pb.QueryEventByBlockRangeRequest, stream pbv2.Spork_StreamEventsByBlockRangeServer) error {
+	if err := ValidateQuery(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	start, end, ok, err := HeightRange(stream.Context(), s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return queryStatusError(err)
+	}
+	if !ok {
+		return nil
+	}
+	log.Info(fmt.Sprintf("grpc v2 stream %v, from %d to %d", req.EventTypes(), start, end))
+
+	enricher := NewRequestEnricher(s.flowClient, req)
+	err = s.flowClient.StreamEventByBlockRange(stream.Context(), req.EventTypes(), start, end, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		// stop fetching once the client is gone
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+		events, err := EventsToV2(stream.Context(), enricher, req, blockEvents)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&pbv2.StreamEventsResponse{
+			Start:  start,
+			End:    end,
+			Cursor: end + 1,
+			Events: events,
+		})
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *SporkServerV2) QueryBlockAtTime(ctx context.Context, req *pb.QueryBlockAtTimeRequest) (*pb.QueryBlockAtTimeResponse, error) {
+	if req.Timestamp == "" {
+		return nil, status.Error(codes.InvalidArgument, (&ValidationError{Field: "timestamp", Message: "timestamp is required"}).Error())
+	}
+	resp, err := BlockAtTime(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+	return resp, nil
+}
+
+func (s *SporkServerV2) AggregateEvents(ctx context.Context, req *pb.AggregateEventsRequest) (*pb.AggregateEventsResponse, error) {
+	if _, err := ValidateAggregate(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := AggregateEvents(ctx, s.flowClient, req)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, queryStatusError(err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+/**
+ * server/validate.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+// eventTypePattern matches contract events, A.<address>.<Contract>.<Event>, and the core flow.<Event> events
+var eventTypePattern = regexp.MustCompile(`^(A\.[0-9a-fA-F]{16}\.[A-Za-z_][A-Za-z0-9_]*|flow)\.[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidationError rejects a malformed request, Field names the offending request field
+type ValidationError struct {
+	Field string
+
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// ValidateEventType checks that eventType is A.<address>.<Contract>.<Event> or flow.<Event>
+func ValidateEventType(eventType string) error {
+	if !eventTypePattern.MatchString(eventType) {
+		return &ValidationError{Field: "events", Message: fmt.Sprintf("%q is not A.<address>.<Contract>.<Event> or flow.<Event>", eventType)}
+	}
+	return nil
+}
+
+// ValidateQuery checks a QueryEventByBlockRange request before anything is fetched, the v2 APIs run it on every request
+func ValidateQuery(req *pb.QueryEventByBlockRangeRequest) error {
+	eventTypes := req.EventTypes()
+	if len(eventTypes) == 0 {
+		return &ValidationError{Field: "events", Message: "at least one event type is required"}
+	}
+	for _, eventType := range eventTypes {
+		if err := ValidateEventType(eventType); err != nil {
+			return err
+		}
+	}
+
+	if HasTimeRange(req) {
+		if req.Start != 0 || req.End != 0 {
+			return &ValidationError{Field: "start", Message: "start and end cannot be combined with startTime and endTime"}
+		}
+		if req.StartTime == "" || req.EndTime == "" {
+			return &ValidationError{Field: "startTime", Message: "startTime and endTime are both required"}
+		}
+	} else if req.Start > req.End {
+		return &ValidationError{Field: "end", Message: fmt.Sprintf("end %d is before start %d", req.End, req.Start)}
+	}
+
+	if _, err := spork.NewEventFilter(req.Filter); err != nil {
+		return err
+	}
+
+	if req.Partial && IsPaged(req) {
+		return &ValidationError{Field: "partial", Message: ErrPagedPartial.Error()}
+	}
+	return nil
+}
+
+// ValidateAggregate checks an AggregateEvents request and returns its time bucket, 0 when it has none
+func ValidateAggregate(req *pb.AggregateEventsRequest) (time.Duration, error) {
+	if req.Query == nil {
+		return 0, &ValidationError{Field: "query", Message: "query is required"}
+	}
+	if err := ValidateQuery(req.Query); err != nil {
+		return 0, err
+	}
+	if IsPaged(req.Query) || req.Query.Partial {
+		return 0, &ValidationError{Field: "query", Message: "limit, cursor and partial are not supported by aggregations"}
+	}
+	if req.Bucket == "" {
+		return 0, nil
+	}
+	bucket, err := time.ParseDuration(req.Bucket)
+	if err != nil || bucket <= 0 {
+		return 0, &ValidationError{Field: "bucket", Message: fmt.Sprintf("%q is not a positive duration such as 1h", req.Bucket)}
+	}
+	return bucket, nil
+}
+
+// InvalidField names the request field rejected by err, empty when err is not about a field
+func InvalidField(err error) string {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Field
+	case errors.Is(err, spork.ErrInvalidCursor):
+		return "cursor"
+	case errors.Is(err, ErrInvalidTimeRange):
+		return "startTime"
+	case errors.Is(err, ErrInvalidTimestamp):
+		return "timestamp"
+	case errors.Is(err, ErrPagedPartial):
+		return "partial"
+	case errors.Is(err, spork.ErrInvalidFilter):
+		return "filter"
+	case errors.Is(err, spork.ErrTooManyGroups):
+		return "groupBy"
+	case errors.Is(err, spork.ErrRangeTooLarge):
+		return "end"
+	default:
+		return ""
+	}
+}
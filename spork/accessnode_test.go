@@ -0,0 +1,246 @@
+package spork
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeAccessNode is an in-process access node with one deposit every fifth block
+type fakeAccessNode struct {
+	access.UnimplementedAccessAPIServer
+	sync.Mutex
+
+	head uint64
+
+	// latency is added to every GetEventsForHeightRange call
+	latency time.Duration
+
+	// headLatency is added to every GetLatestBlockHeader call
+	headLatency time.Duration
+
+	// blockLatency is added for every block of the requested range
+	blockLatency time.Duration
+
+	// maxRange rejects wider height ranges like a real node does, 0 accepts any range
+	maxRange uint64
+
+	// err fails every GetEventsForHeightRange call
+	err error
+
+	// failFor limits err to the first calls, 0 fails every call
+	failFor int
+
+	// badHeights fail every range covering one of them, whatever its size
+	badHeights []uint64
+
+	// apiKey, when set, is required in the api_key header of every call
+	apiKey string
+
+	calls int
+
+	headerCalls int
+
+	txCalls int
+
+	inFlight int
+
+	maxInFlight int
+}
+
+// checkAPIKey rejects calls without the expected api_key header
+func (node *fakeAccessNode) checkAPIKey(ctx context.Context) error {
+	if node.apiKey == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get("api_key"); len(keys) != 1 || keys[0] != node.apiKey {
+		return status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	return nil
+}
+
+func (node *fakeAccessNode) Ping(ctx context.Context, req *access.PingRequest) (*access.PingResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	return &access.PingResponse{}, nil
+}
+
+func (node *fakeAccessNode) GetLatestBlock(ctx context.Context, req *access.GetLatestBlockRequest) (*access.BlockResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	node.Lock()
+	defer node.Unlock()
+	return &access.BlockResponse{Block: &entities.Block{
+		Height:    node.head,
+		Timestamp: timestamppb.New(time.Unix(int64(node.head), 0)),
+	}}, nil
+}
+
+func (node *fakeAccessNode) GetLatestBlockHeader(ctx context.Context, req *access.GetLatestBlockHeaderRequest) (*access.BlockHeaderResponse, error) {
+	time.Sleep(node.headLatency)
+	node.Lock()
+	defer node.Unlock()
+	return &access.BlockHeaderResponse{Block: &entities.BlockHeader{
+		Height:    node.head,
+		Timestamp: timestamppb.New(time.Unix(int64(node.head), 0)),
+	}}, nil
+}
+
+// GetBlockHeaderByHeight serves the blocks up to head, the block at height h is made at second h
+func (node *fakeAccessNode) GetBlockHeaderByHeight(ctx context.Context, req *access.GetBlockHeaderByHeightRequest) (*access.BlockHeaderResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	node.Lock()
+	defer node.Unlock()
+	node.headerCalls++
+	if req.Height > node.head {
+		return nil, status.Errorf(codes.NotFound, "block %d not found", req.Height)
+	}
+	return &access.BlockHeaderResponse{Block: &entities.BlockHeader{
+		Id:        []byte{byte(req.Height >> 8), byte(req.Height)},
+		Height:    req.Height,
+		Timestamp: timestamppb.New(time.Unix(int64(req.Height), 0)),
+	}}, nil
+}
+
+// GetTransaction serves any transaction, paid and proposed by 0x01 and authorized by 0x02
+func (node *fakeAccessNode) GetTransaction(ctx context.Context, req *access.GetTransactionRequest) (*access.TransactionResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	node.Lock()
+	defer node.Unlock()
+	node.txCalls++
+	return &access.TransactionResponse{Transaction: &entities.Transaction{
+		ProposalKey: &entities.Transaction_ProposalKey{Address: []byte{0x01}},
+		Payer:       []byte{0x01},
+		Authorizers: [][]byte{{0x02}},
+	}}, nil
+}
+
+func (node *fakeAccessNode) GetTransactionResult(ctx context.Context, req *access.GetTransactionRequest) (*access.TransactionResultResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	return &access.TransactionResultResponse{Status: entities.TransactionStatus_SEALED}, nil
+}
+
+func (node *fakeAccessNode) GetEventsForHeightRange(ctx context.Context, req *access.GetEventsForHeightRangeRequest) (*access.EventsResponse, error) {
+	if err := node.checkAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	node.Lock()
+	node.calls++
+	failing := node.err != nil && (node.failFor == 0 || node.calls <= node.failFor)
+	node.inFlight++
+	if node.inFlight > node.maxInFlight {
+		node.maxInFlight = node.inFlight
+	}
+	node.Unlock()
+	defer func() {
+		node.Lock()
+		node.inFlight--
+		node.Unlock()
+	}()
+
+	select {
+	case <-time.After(node.latency + time.Duration(req.EndHeight-req.StartHeight+1)*node.blockLatency):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	if failing {
+		return nil, node.err
+	}
+	if node.maxRange > 0 && req.EndHeight-req.StartHeight+1 > node.maxRange {
+		return nil, status.Error(codes.ResourceExhausted, "height range too large")
+	}
+	for _, height := range node.badHeights {
+		if height >= req.StartHeight && height <= req.EndHeight {
+			return nil, status.Errorf(codes.Internal, "failed to read block %d", height)
+		}
+	}
+
+	payload, err := jsoncdc.Encode(newTestDepositEvent(0).Value)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*access.EventsResponse_Result, 0, req.EndHeight-req.StartHeight+1)
+	for height := req.StartHeight; height <= req.EndHeight; height++ {
+		result := &access.EventsResponse_Result{
+			BlockId:        []byte{byte(height >> 8), byte(height)},
+			BlockHeight:    height,
+			BlockTimestamp: timestamppb.New(time.Unix(int64(height), 0)),
+			Events:         []*entities.Event{},
+		}
+		if height%5 == 0 {
+			result.Events = append(result.Events, &entities.Event{
+				Type:          req.Type,
+				TransactionId: []byte{0x02},
+				Payload:       payload,
+			})
+		}
+		results = append(results, result)
+	}
+	return &access.EventsResponse{Results: results}, nil
+}
+
+// callCount reads calls while cancelled calls may still be running
+func (node *fakeAccessNode) callCount() int {
+	node.Lock()
+	defer node.Unlock()
+	return node.calls
+}
+
+func (node *fakeAccessNode) headerCallCount() int {
+	node.Lock()
+	defer node.Unlock()
+	return node.headerCalls
+}
+
+// newFakeAccessNodeClient serves node in-process and returns a flow client connected to it
+func newFakeAccessNodeClient(tb testing.TB, node *fakeAccessNode) *client.Client {
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	access.RegisterAccessAPIServer(grpcServer, node)
+	go grpcServer.Serve(lis)
+	tb.Cleanup(grpcServer.Stop)
+
+	flowClient, err := client.New("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	require.Nil(tb, err)
+	tb.Cleanup(func() { flowClient.Close() })
+	return flowClient
+}
+
+// newFakeAccessNodeAddr serves node on a local tcp port and returns its address
+func newFakeAccessNodeAddr(tb testing.TB, node *fakeAccessNode) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(tb, err)
+	grpcServer := grpc.NewServer()
+	access.RegisterAccessAPIServer(grpcServer, node)
+	go grpcServer.Serve(lis)
+	tb.Cleanup(grpcServer.Stop)
+	return lis.Addr().String()
+}
@@ -0,0 +1,168 @@
+/**
+ * spork/aggregate.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// ErrTooManyGroups is returned once an aggregation holds more than maxAggregateGroups groups
+var ErrTooManyGroups = errors.New("too many groups, narrow the range, the groupBy field or the bucket")
+
+// maxAggregateGroups bounds the memory held by one aggregation
+const maxAggregateGroups = 10000
+
+// aggregateKey identifies a group, bucket is the zero time without a time bucket
+type aggregateKey struct {
+	bucket time.Time
+
+	key string
+}
+
+// aggregate is the running count, sum, min and max of a group
+type aggregate struct {
+	count uint64
+
+	sum *big.Rat
+
+	min *big.Rat
+
+	max *big.Rat
+}
+
+// EventAggregator counts the events it is given and sums up the numbers of one of their fields, by group
+type EventAggregator struct {
+	field string
+
+	groupBy string
+
+	bucket time.Duration
+
+	// scale is the most decimals seen in field, sums are printed with as many
+	scale int
+
+	groups map[aggregateKey]*aggregate
+}
+
+// NewEventAggregator groups by the value of groupBy and by block time buckets of bucket, each when set.
+// An empty field only counts events.
+func NewEventAggregator(field string, groupBy string, bucket time.Duration) *EventAggregator {
+	return &EventAggregator{
+		field:   field,
+		groupBy: groupBy,
+		bucket:  bucket,
+		groups:  make(map[aggregateKey]*aggregate),
+	}
+}
+
+// Add aggregates every event of blockEvents, events missing the field or holding no number in it are only counted
+func (aggregator *EventAggregator) Add(blockEvents []client.BlockEvents) error {
+	for _, blockEvent := range blockEvents {
+		var bucket time.Time
+		if aggregator.bucket > 0 {
+			bucket = blockEvent.BlockTimestamp.UTC().Truncate(aggregator.bucket)
+		}
+		for _, event := range blockEvent.Events {
+			key := aggregateKey{bucket: bucket}
+			if aggregator.groupBy != "" {
+				if value, ok := eventField(&event.Value, aggregator.groupBy); ok {
+					key.key, _ = textOf(value)
+				}
+			}
+			group, ok := aggregator.groups[key]
+			if !ok {
+				if len(aggregator.groups) == maxAggregateGroups {
+					return ErrTooManyGroups
+				}
+				group = &aggregate{}
+				aggregator.groups[key] = group
+			}
+			group.count++
+
+			if aggregator.field == "" {
+				continue
+			}
+			value, ok := eventField(&event.Value, aggregator.field)
+			if !ok {
+				continue
+			}
+			number := numberOf(value)
+			if number == nil {
+				continue
+			}
+			aggregator.observeScale(value.String())
+			if group.sum == nil {
+				group.sum, group.min, group.max = new(big.Rat), number, number
+			}
+			group.sum.Add(group.sum, number)
+			if number.Cmp(group.min) < 0 {
+				group.min = number
+			}
+			if number.Cmp(group.max) > 0 {
+				group.max = number
+			}
+		}
+	}
+	return nil
+}
+
+// observeScale keeps the most decimals of the field, UFix64 values always have 8
+func (aggregator *EventAggregator) observeScale(number string) {
+	if dot := strings.IndexByte(number, '.'); dot >= 0 && len(number)-dot-1 > aggregator.scale {
+		aggregator.scale = len(number) - dot - 1
+	}
+}
+
+// Results returns the groups ordered by bucket, then by key
+func (aggregator *EventAggregator) Results() []*pb.EventAggregate {
+	keys := make([]aggregateKey, 0, len(aggregator.groups))
+	for key := range aggregator.groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].bucket.Equal(keys[j].bucket) {
+			return keys[i].bucket.Before(keys[j].bucket)
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	result := make([]*pb.EventAggregate, 0, len(keys))
+	for _, key := range keys {
+		group := aggregator.groups[key]
+		jsonGroup := &pb.EventAggregate{Key: key.key, Count: group.count}
+		if aggregator.bucket > 0 {
+			jsonGroup.BucketStart = timestamppb.New(key.bucket)
+		}
+		if group.sum != nil {
+			jsonGroup.Sum = group.sum.FloatString(aggregator.scale)
+			jsonGroup.Min = group.min.FloatString(aggregator.scale)
+			jsonGroup.Max = group.max.FloatString(aggregator.scale)
+		}
+		result = append(result, jsonGroup)
+	}
+	return result
+}
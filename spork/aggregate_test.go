@@ -0,0 +1,71 @@
+package spork
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventAggregator(t *testing.T) {
+	alice := flow.HexToAddress("1654653399040a61")
+	bob := flow.HexToAddress("f233dcee88fe0abe")
+	hour := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	blockEvents := []client.BlockEvents{
+		{Height: 1, BlockTimestamp: hour.Add(5 * time.Minute), Events: []flow.Event{
+			{Value: newFilterTestEvent("1.5", &alice)},
+			{Value: newFilterTestEvent("2.25", &bob)},
+		}},
+		{Height: 2, BlockTimestamp: hour.Add(50 * time.Minute), Events: []flow.Event{
+			{Value: newFilterTestEvent("10.0", &alice)},
+		}},
+		{Height: 3, BlockTimestamp: hour.Add(70 * time.Minute), Events: []flow.Event{
+			{Value: newFilterTestEvent("0.5", nil)},
+		}},
+	}
+
+	aggregator := NewEventAggregator("amount", "", 0)
+	require.Nil(t, aggregator.Add(blockEvents))
+	groups := aggregator.Results()
+	require.Len(t, groups, 1)
+	require.Equal(t, uint64(4), groups[0].Count)
+	require.Equal(t, "14.25000000", groups[0].Sum)
+	require.Equal(t, "0.50000000", groups[0].Min)
+	require.Equal(t, "10.00000000", groups[0].Max)
+	require.Nil(t, groups[0].BucketStart)
+
+	aggregator = NewEventAggregator("amount", "to", time.Hour)
+	require.Nil(t, aggregator.Add(blockEvents[:2]))
+	require.Nil(t, aggregator.Add(blockEvents[2:]))
+	groups = aggregator.Results()
+	require.Len(t, groups, 3)
+	require.Equal(t, "0x"+alice.Hex(), groups[0].Key)
+	require.Equal(t, hour, groups[0].BucketStart.AsTime())
+	require.Equal(t, uint64(2), groups[0].Count)
+	require.Equal(t, "11.50000000", groups[0].Sum)
+	require.Equal(t, "0x"+bob.Hex(), groups[1].Key)
+	require.Equal(t, "2.25000000", groups[1].Sum)
+	require.Equal(t, "", groups[2].Key, "events without the groupBy field share the empty key")
+	require.Equal(t, hour.Add(time.Hour), groups[2].BucketStart.AsTime())
+
+	aggregator = NewEventAggregator("memo", "", 0)
+	require.Nil(t, aggregator.Add(blockEvents))
+	groups = aggregator.Results()
+	require.Equal(t, uint64(4), groups[0].Count)
+	require.Empty(t, groups[0].Sum, "a text field is only counted")
+}
+
+func TestEventAggregatorTooManyGroups(t *testing.T) {
+	aggregator := NewEventAggregator("", "amount", 0)
+	blockEvents := make([]client.BlockEvents, 0, maxAggregateGroups+1)
+	for i := 0; i <= maxAggregateGroups; i++ {
+		blockEvents = append(blockEvents, client.BlockEvents{
+			Height: uint64(i),
+			Events: []flow.Event{{Value: newFilterTestEvent(strconv.Itoa(i)+".0", nil)}},
+		})
+	}
+	require.ErrorIs(t, aggregator.Add(blockEvents), ErrTooManyGroups)
+}
@@ -0,0 +1,162 @@
+/**
+ * spork/balancer.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// strategies ordering the healthy access nodes of a spork
+const (
+	BalanceRoundRobin = "roundrobin"
+	BalanceLatency    = "latency"
+)
+
+// nodeHealth is what NodeBalancer knows about one access node
+type nodeHealth struct {
+	healthy bool
+
+	failures int
+
+	// latency is a moving average of the successful calls
+	latency time.Duration
+
+	// retryAt is when an unhealthy node may be pinged again
+	retryAt time.Time
+}
+
+// NodeBalancer orders the access nodes of a spork for every batch and tracks their health.
+// Failing nodes go to the back of the order until a Ping succeeds again.
+type NodeBalancer struct {
+	sync.Mutex
+
+	strategy string
+
+	cooldown time.Duration
+
+	next int
+
+	nodes map[string]*nodeHealth
+}
+
+func NewNodeBalancer(strategy string, cooldown time.Duration) (*NodeBalancer, error) {
+	if strategy != BalanceRoundRobin && strategy != BalanceLatency {
+		return nil, fmt.Errorf("unknown balance strategy %s", strategy)
+	}
+	return &NodeBalancer{
+		strategy: strategy,
+		cooldown: cooldown,
+		nodes:    make(map[string]*nodeHealth),
+	}, nil
+}
+
+func (balancer *NodeBalancer) health(node string) *nodeHealth {
+	health, ok := balancer.nodes[node]
+	if !ok {
+		health = &nodeHealth{healthy: true}
+		balancer.nodes[node] = health
+	}
+	return health
+}
+
+// Order returns the nodes in the order they should be tried, healthy nodes first
+func (balancer *NodeBalancer) Order(nodes []string) []string {
+	balancer.Lock()
+	defer balancer.Unlock()
+
+	healthy := make([]string, 0, len(nodes))
+	unhealthy := make([]string, 0)
+	for _, node := range nodes {
+		if balancer.health(node).healthy {
+			healthy = append(healthy, node)
+		} else {
+			unhealthy = append(unhealthy, node)
+		}
+	}
+
+	switch balancer.strategy {
+	case BalanceLatency:
+		// nodes without a measurement yet come first so they get one
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return balancer.nodes[healthy[i]].latency < balancer.nodes[healthy[j]].latency
+		})
+	default:
+		if len(healthy) > 1 {
+			offset := balancer.next % len(healthy)
+			healthy = append(healthy[offset:], healthy[:offset]...)
+		}
+		balancer.next++
+	}
+
+	// unhealthy nodes are still tried as the last resort, the longest failing last
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return balancer.nodes[unhealthy[i]].retryAt.Before(balancer.nodes[unhealthy[j]].retryAt)
+	})
+	return append(healthy, unhealthy...)
+}
+
+// Healthy reports whether node can be used without pinging it first
+func (balancer *NodeBalancer) Healthy(node string) bool {
+	balancer.Lock()
+	defer balancer.Unlock()
+	return balancer.health(node).healthy
+}
+
+// ShouldPing reports whether an unhealthy node is due for a Ping check
+func (balancer *NodeBalancer) ShouldPing(node string) bool {
+	balancer.Lock()
+	defer balancer.Unlock()
+	health := balancer.health(node)
+	return !health.healthy && !time.Now().Before(health.retryAt)
+}
+
+// ReportSuccess marks node healthy and records the latency of the call
+func (balancer *NodeBalancer) ReportSuccess(node string, latency time.Duration) {
+	balancer.Lock()
+	defer balancer.Unlock()
+	health := balancer.health(node)
+	health.healthy = true
+	health.failures = 0
+	if health.latency == 0 {
+		health.latency = latency
+	} else {
+		health.latency = (health.latency*4 + latency) / 5
+	}
+}
+
+// MarkHealthy marks node healthy again without a latency sample, e.g. after a successful Ping
+func (balancer *NodeBalancer) MarkHealthy(node string) {
+	balancer.Lock()
+	defer balancer.Unlock()
+	health := balancer.health(node)
+	health.healthy = true
+	health.failures = 0
+}
+
+// ReportFailure marks node unhealthy until it answers a Ping after the cooldown
+func (balancer *NodeBalancer) ReportFailure(node string) {
+	balancer.Lock()
+	defer balancer.Unlock()
+	health := balancer.health(node)
+	health.healthy = false
+	health.failures++
+	health.retryAt = time.Now().Add(balancer.cooldown)
+}
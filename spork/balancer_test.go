@@ -0,0 +1,63 @@
+package spork
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeBalancerRoundRobin(t *testing.T) {
+	balancer, err := NewNodeBalancer(BalanceRoundRobin, time.Minute)
+	require.Nil(t, err)
+
+	nodes := []string{"a", "b", "c"}
+	require.Equal(t, []string{"a", "b", "c"}, balancer.Order(nodes))
+	require.Equal(t, []string{"b", "c", "a"}, balancer.Order(nodes))
+	require.Equal(t, []string{"c", "a", "b"}, balancer.Order(nodes))
+
+	balancer.ReportFailure("b")
+	require.False(t, balancer.Healthy("b"))
+	require.False(t, balancer.ShouldPing("b"), "b is cooling down")
+	require.Equal(t, []string{"c", "a", "b"}, balancer.Order(nodes))
+
+	balancer.ReportSuccess("b", time.Millisecond)
+	require.True(t, balancer.Healthy("b"))
+}
+
+func TestNodeBalancerLatency(t *testing.T) {
+	balancer, err := NewNodeBalancer(BalanceLatency, 0)
+	require.Nil(t, err)
+
+	nodes := []string{"a", "b", "c"}
+	balancer.ReportSuccess("a", 30*time.Millisecond)
+	balancer.ReportSuccess("b", 10*time.Millisecond)
+	require.Equal(t, []string{"c", "b", "a"}, balancer.Order(nodes), "unmeasured nodes first")
+
+	balancer.ReportSuccess("c", 20*time.Millisecond)
+	require.Equal(t, []string{"b", "c", "a"}, balancer.Order(nodes))
+
+	balancer.ReportFailure("b")
+	require.True(t, balancer.ShouldPing("b"), "no cooldown")
+	require.Equal(t, []string{"c", "a", "b"}, balancer.Order(nodes))
+
+	_, err = NewNodeBalancer("random", 0)
+	require.NotNil(t, err)
+}
+
+func TestNodeBalancerMarkHealthyKeepsLatency(t *testing.T) {
+	balancer, err := NewNodeBalancer(BalanceLatency, 0)
+	require.Nil(t, err)
+
+	nodes := []string{"a", "b"}
+	balancer.ReportSuccess("a", 30*time.Millisecond)
+	balancer.ReportSuccess("b", 10*time.Millisecond)
+	balancer.ReportFailure("a")
+	require.True(t, balancer.ShouldPing("a"))
+
+	// a successful ping brings a back without pretending it answered in no time
+	balancer.MarkHealthy("a")
+	require.True(t, balancer.Healthy("a"))
+	require.False(t, balancer.ShouldPing("a"))
+	require.Equal(t, []string{"b", "a"}, balancer.Order(nodes))
+}
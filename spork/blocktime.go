@@ -0,0 +1,138 @@
+/**
+ * spork/blocktime.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxBlockTimeSamples bounds the block timestamps kept by a blockTimeIndex
+const maxBlockTimeSamples = 4096
+
+// blockTime is the timestamp of the block at Height
+type blockTime struct {
+	Height uint64
+
+	Timestamp time.Time
+}
+
+// blockTimestampFunc reads the timestamp of the block at height from an access node
+type blockTimestampFunc func(ctx context.Context, height uint64) (time.Time, error)
+
+// blockTimeIndex caches the block timestamps read while resolving times to heights,
+// later searches start from the closest known blocks instead of the whole range
+type blockTimeIndex struct {
+	sync.Mutex
+
+	// samples are sorted by height
+	samples []blockTime
+
+	maxSamples int
+}
+
+func newBlockTimeIndex(maxSamples int) *blockTimeIndex {
+	return &blockTimeIndex{samples: make([]blockTime, 0), maxSamples: maxSamples}
+}
+
+// Len returns the number of cached block timestamps
+func (index *blockTimeIndex) Len() int {
+	index.Lock()
+	defer index.Unlock()
+	return len(index.samples)
+}
+
+func (index *blockTimeIndex) lookup(height uint64) (time.Time, bool) {
+	index.Lock()
+	defer index.Unlock()
+	i := sort.Search(len(index.samples), func(i int) bool { return index.samples[i].Height >= height })
+	if i < len(index.samples) && index.samples[i].Height == height {
+		return index.samples[i].Timestamp, true
+	}
+	return time.Time{}, false
+}
+
+func (index *blockTimeIndex) add(height uint64, timestamp time.Time) {
+	index.Lock()
+	defer index.Unlock()
+	i := sort.Search(len(index.samples), func(i int) bool { return index.samples[i].Height >= height })
+	if i < len(index.samples) && index.samples[i].Height == height {
+		return
+	}
+	index.samples = append(index.samples, blockTime{})
+	copy(index.samples[i+1:], index.samples[i:])
+	index.samples[i] = blockTime{Height: height, Timestamp: timestamp}
+
+	// drop every other sample, the remaining ones still narrow any search
+	if index.maxSamples > 0 && len(index.samples) > index.maxSamples {
+		thinned := index.samples[:0]
+		for j := 0; j < len(index.samples); j += 2 {
+			thinned = append(thinned, index.samples[j])
+		}
+		index.samples = thinned
+	}
+}
+
+// timestamp returns the timestamp of the block at height, fetched only when not cached
+func (index *blockTimeIndex) timestamp(ctx context.Context, height uint64, fetch blockTimestampFunc) (time.Time, error) {
+	if timestamp, ok := index.lookup(height); ok {
+		return timestamp, nil
+	}
+	timestamp, err := fetch(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	index.add(height, timestamp)
+	return timestamp, nil
+}
+
+// search returns the first height of [lo, hi] whose block is not older than timestamp, hi+1 when every block is.
+// Block timestamps grow with the height, so a binary search over the headers finds it.
+func (index *blockTimeIndex) search(ctx context.Context, timestamp time.Time, lo uint64, hi uint64, fetch blockTimestampFunc) (uint64, error) {
+	left, right := lo, hi+1
+
+	// the cached blocks around timestamp narrow the range before any header is fetched
+	index.Lock()
+	for _, sample := range index.samples {
+		if sample.Height < left || sample.Height >= right {
+			continue
+		}
+		if sample.Timestamp.Before(timestamp) {
+			left = sample.Height + 1
+		} else {
+			right = sample.Height
+		}
+	}
+	index.Unlock()
+
+	for left < right {
+		mid := left + (right-left)/2
+		blockTimestamp, err := index.timestamp(ctx, mid, fetch)
+		if err != nil {
+			return 0, err
+		}
+		if blockTimestamp.Before(timestamp) {
+			left = mid + 1
+		} else {
+			right = mid
+		}
+	}
+	return left, nil
+}
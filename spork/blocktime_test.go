@@ -0,0 +1,136 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tenSecondBlocks makes the block at height h at second 10*h and counts the fetches
+func tenSecondBlocks(fetches *int) blockTimestampFunc {
+	return func(ctx context.Context, height uint64) (time.Time, error) {
+		*fetches++
+		return time.Unix(int64(10*height), 0), nil
+	}
+}
+
+func TestBlockTimeIndexSearch(t *testing.T) {
+	index := newBlockTimeIndex(maxBlockTimeSamples)
+	fetches := 0
+	cases := []struct {
+		timestamp int64
+		height    uint64
+	}{
+		{0, 0},
+		{10, 1},
+		{15, 2},
+		{5000, 500},
+		{5001, 501},
+		{9990, 999},
+		{9991, 1000},
+		{20000, 1000},
+	}
+	for _, c := range cases {
+		height, err := index.search(context.Background(), time.Unix(c.timestamp, 0), 0, 999, tenSecondBlocks(&fetches))
+		require.Nil(t, err)
+		require.Equal(t, c.height, height, "timestamp %d", c.timestamp)
+	}
+
+	fetches = 0
+	for _, c := range cases {
+		height, err := index.search(context.Background(), time.Unix(c.timestamp, 0), 0, 999, tenSecondBlocks(&fetches))
+		require.Nil(t, err)
+		require.Equal(t, c.height, height)
+	}
+	require.Zero(t, fetches, "resolved timestamps should come from the cache")
+}
+
+func TestBlockTimeIndexSearchError(t *testing.T) {
+	index := newBlockTimeIndex(maxBlockTimeSamples)
+	_, err := index.search(context.Background(), time.Unix(100, 0), 0, 999, func(ctx context.Context, height uint64) (time.Time, error) {
+		return time.Time{}, errors.New("node down")
+	})
+	require.EqualError(t, err, "node down")
+}
+
+func TestBlockTimeIndexThinsSamples(t *testing.T) {
+	index := newBlockTimeIndex(8)
+	for height := uint64(0); height < 100; height++ {
+		index.add(height, time.Unix(int64(height), 0))
+		require.LessOrEqual(t, index.Len(), 8)
+	}
+
+	fetches := 0
+	height, err := index.search(context.Background(), time.Unix(5000, 0), 0, 9999, tenSecondBlocks(&fetches))
+	require.Nil(t, err)
+	require.Equal(t, uint64(500), height)
+}
+
+func TestSporkStoreQueryBlockHeightByTime(t *testing.T) {
+	first := &fakeAccessNode{head: 149}
+	second := &fakeAccessNode{head: 300}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first))
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss.SporkList = append(ss.SporkList, Spork{Name: "spork2", RootHeight: 150, AccessNode: secondAddr, AccessNodes: []string{secondAddr}})
+	require.Nil(t, ss.newReadClient())
+
+	// the fake nodes make the block at height h at second h
+	height, err := ss.QueryBlockHeightByTime(context.Background(), time.Unix(99, 500))
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), height)
+	require.Equal(t, 1, second.headerCallCount(), "only the root block of the second spork should be read")
+
+	height, err = ss.QueryBlockHeightByTime(context.Background(), time.Unix(200, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(200), height)
+
+	height, err = ss.QueryBlockHeightByTime(context.Background(), time.Unix(150, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(150), height)
+
+	height, err = ss.QueryBlockHeightByTime(context.Background(), time.Unix(1000, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(301), height, "a timestamp after the sealed head resolves past it")
+
+	calls := first.headerCallCount() + second.headerCallCount()
+	height, err = ss.QueryBlockHeightByTime(context.Background(), time.Unix(99, 500))
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), height)
+	require.Equal(t, calls, first.headerCallCount()+second.headerCallCount(), "a resolved timestamp should come from the cache")
+}
+
+func TestSporkStoreQueryBlockHeightByTimeBeforeFirstSpork(t *testing.T) {
+	node := &fakeAccessNode{head: 300}
+	addr := newFakeAccessNodeAddr(t, node)
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, addr)
+	ss.SporkList[0].RootHeight = 100
+	require.Nil(t, ss.newReadClient())
+
+	height, err := ss.QueryBlockHeightByTime(context.Background(), time.Unix(10, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), height)
+}
+
+func TestSporkStoreQueryBlockHeaderByHeight(t *testing.T) {
+	first := &fakeAccessNode{head: 149}
+	second := &fakeAccessNode{head: 300}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first))
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss.SporkList = append(ss.SporkList, Spork{Name: "spork2", RootHeight: 150, AccessNode: secondAddr, AccessNodes: []string{secondAddr}})
+
+	// a historical block is read from the nodes of its own spork
+	header, err := ss.QueryBlockHeaderByHeight(context.Background(), 120)
+	require.Nil(t, err)
+	require.Equal(t, uint64(120), header.Height)
+	require.Equal(t, time.Unix(120, 0), header.Timestamp.Local())
+	require.Equal(t, 1, first.headerCallCount())
+	require.Zero(t, second.headerCallCount())
+
+	header, err = ss.QueryBlockHeaderByHeight(context.Background(), 250)
+	require.Nil(t, err)
+	require.Equal(t, uint64(250), header.Height)
+	require.Equal(t, 1, second.headerCallCount())
+}
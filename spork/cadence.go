@@ -0,0 +1,128 @@
+/**
+ * spork/cadence.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"github.com/onflow/cadence"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// cadenceTypeID returns the type id, or "" when the type is unknown.
+// Decoded values may carry partially filled types, ID() panics on those.
+func cadenceTypeID(t cadence.Type) (id string) {
+	if t == nil {
+		return ""
+	}
+	defer func() {
+		if recover() != nil {
+			id = ""
+		}
+	}()
+	return t.ID()
+}
+
+func cadenceComposite(kind string, fieldTypes []cadence.Field, values []cadence.Value) *pb.CadenceValue_Composite {
+	fields := make([]*pb.CadenceField, 0, len(values))
+	for i, value := range values {
+		name := ""
+		if i < len(fieldTypes) {
+			name = fieldTypes[i].Identifier
+		}
+		fields = append(fields, &pb.CadenceField{Name: name, Value: CadenceValueToProto(value)})
+	}
+	return &pb.CadenceValue_Composite{Composite: &pb.CadenceComposite{Kind: kind, Fields: fields}}
+}
+
+// CadenceValueToProto converts a cadence value into its structured form
+func CadenceValueToProto(value cadence.Value) *pb.CadenceValue {
+	if value == nil {
+		return nil
+	}
+
+	result := &pb.CadenceValue{Type: cadenceTypeID(value.Type())}
+	switch v := value.(type) {
+	case cadence.Void:
+	case cadence.Bool:
+		result.Value = &pb.CadenceValue_Boolean{Boolean: bool(v)}
+	case cadence.String:
+		result.Value = &pb.CadenceValue_Scalar{Scalar: string(v)}
+	case cadence.Optional:
+		result.Value = &pb.CadenceValue_Optional{Optional: &pb.CadenceOptional{Value: CadenceValueToProto(v.Value)}}
+	case cadence.Array:
+		values := make([]*pb.CadenceValue, 0, len(v.Values))
+		for _, element := range v.Values {
+			values = append(values, CadenceValueToProto(element))
+		}
+		result.Value = &pb.CadenceValue_Array{Array: &pb.CadenceArray{Values: values}}
+	case cadence.Dictionary:
+		entries := make([]*pb.CadenceKeyValue, 0, len(v.Pairs))
+		for _, pair := range v.Pairs {
+			entries = append(entries, &pb.CadenceKeyValue{
+				Key:   CadenceValueToProto(pair.Key),
+				Value: CadenceValueToProto(pair.Value),
+			})
+		}
+		result.Value = &pb.CadenceValue_Dictionary{Dictionary: &pb.CadenceDictionary{Entries: entries}}
+	case cadence.Struct:
+		var fieldTypes []cadence.Field
+		if v.StructType != nil {
+			fieldTypes = v.StructType.Fields
+		}
+		result.Value = cadenceComposite("struct", fieldTypes, v.Fields)
+	case cadence.Resource:
+		var fieldTypes []cadence.Field
+		if v.ResourceType != nil {
+			fieldTypes = v.ResourceType.Fields
+		}
+		result.Value = cadenceComposite("resource", fieldTypes, v.Fields)
+	case cadence.Event:
+		var fieldTypes []cadence.Field
+		if v.EventType != nil {
+			fieldTypes = v.EventType.Fields
+		}
+		result.Value = cadenceComposite("event", fieldTypes, v.Fields)
+	case cadence.Contract:
+		var fieldTypes []cadence.Field
+		if v.ContractType != nil {
+			fieldTypes = v.ContractType.Fields
+		}
+		result.Value = cadenceComposite("contract", fieldTypes, v.Fields)
+	case cadence.Enum:
+		var fieldTypes []cadence.Field
+		if v.EnumType != nil {
+			fieldTypes = v.EnumType.Fields
+		}
+		result.Value = cadenceComposite("enum", fieldTypes, v.Fields)
+	case cadence.Path:
+		result.Value = &pb.CadenceValue_Scalar{Scalar: v.String()}
+	case cadence.TypeValue:
+		result.Value = &pb.CadenceValue_Scalar{Scalar: v.StaticType}
+	case cadence.Capability:
+		result.Value = &pb.CadenceValue_Scalar{Scalar: v.String()}
+	case cadence.Link:
+		// links have no runtime type
+		result.Type = "Link"
+		result.Value = &pb.CadenceValue_Scalar{Scalar: v.String()}
+	default:
+		// numbers, addresses and bytes keep their cadence formatting,
+		// fixed point numbers stay decimal strings so no precision is lost
+		result.Value = &pb.CadenceValue_Scalar{Scalar: v.String()}
+	}
+	return result
+}
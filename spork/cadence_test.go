@@ -0,0 +1,70 @@
+package spork
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/stretchr/testify/require"
+)
+
+func marshalCadenceValue(t *testing.T, value cadence.Value) string {
+	ret, err := json.Marshal(CadenceValueToProto(value))
+	require.Nil(t, err)
+	return string(ret)
+}
+
+func TestCadenceValueToProtoScalars(t *testing.T) {
+	amount, _ := cadence.NewUFix64("1.5")
+	require.Equal(t, `{"type":"UFix64","value":"1.50000000"}`, marshalCadenceValue(t, amount))
+	require.Equal(t, `{"type":"UInt64","value":"42"}`, marshalCadenceValue(t, cadence.NewUInt64(42)))
+	require.Equal(t, `{"type":"String","value":"hello"}`, marshalCadenceValue(t, cadence.String("hello")))
+	require.Equal(t, `{"type":"Bool","value":true}`, marshalCadenceValue(t, cadence.NewBool(true)))
+	require.Equal(t, `{"type":"Address","value":"0x1654653399040a61"}`,
+		marshalCadenceValue(t, cadence.BytesToAddress([]byte{0x16, 0x54, 0x65, 0x33, 0x99, 0x04, 0x0a, 0x61})))
+	require.Equal(t, "null", marshalCadenceValue(t, nil))
+}
+
+func TestCadenceValueToProtoOptional(t *testing.T) {
+	require.Equal(t, `{"type":"Never?","value":null}`, marshalCadenceValue(t, cadence.NewOptional(nil)))
+	require.Equal(t, `{"type":"UInt64?","value":{"type":"UInt64","value":"7"}}`,
+		marshalCadenceValue(t, cadence.NewOptional(cadence.NewUInt64(7))))
+}
+
+func TestCadenceValueToProtoContainers(t *testing.T) {
+	array := cadence.NewArray([]cadence.Value{cadence.NewUInt64(1), cadence.NewUInt64(2)}).
+		WithType(cadence.VariableSizedArrayType{ElementType: cadence.UInt64Type{}})
+	require.Equal(t, `{"type":"[UInt64]","value":[{"type":"UInt64","value":"1"},{"type":"UInt64","value":"2"}]}`,
+		marshalCadenceValue(t, array))
+
+	dictionary := cadence.NewDictionary([]cadence.KeyValuePair{{Key: cadence.String("a"), Value: cadence.NewUInt64(1)}}).
+		WithType(cadence.DictionaryType{KeyType: cadence.StringType{}, ElementType: cadence.UInt64Type{}})
+	require.Equal(t, `{"type":"{String:UInt64}","value":[{"key":{"type":"String","value":"a"},"value":{"type":"UInt64","value":"1"}}]}`,
+		marshalCadenceValue(t, dictionary))
+
+	// untyped containers do not panic
+	require.Equal(t, `{"type":"","value":[]}`, marshalCadenceValue(t, cadence.NewArray(nil)))
+}
+
+func TestCadenceValueToProtoComposite(t *testing.T) {
+	structType := &cadence.StructType{
+		QualifiedIdentifier: "Metadata",
+		Fields: []cadence.Field{
+			{Identifier: "name", Type: cadence.StringType{}},
+			{Identifier: "id", Type: cadence.UInt64Type{}},
+		},
+	}
+	value := cadence.NewStruct([]cadence.Value{cadence.String("punk"), cadence.NewUInt64(3)}).WithType(structType)
+	require.Equal(t, `{"type":"Metadata","kind":"struct","value":{"name":{"type":"String","value":"punk"},"id":{"type":"UInt64","value":"3"}}}`,
+		marshalCadenceValue(t, value))
+}
+
+func TestEventToJSONTypedValue(t *testing.T) {
+	event := newTestDepositEvent(10).Value
+	values := EventToJSON(&event)
+	require.Len(t, values, 1)
+	require.Equal(t, "amount", values[0].Name)
+	require.Equal(t, "1.50000000", values[0].Value)
+	require.Equal(t, "UFix64", values[0].Type)
+	require.Equal(t, "1.50000000", values[0].TypedValue.GetScalar())
+}
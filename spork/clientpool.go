@@ -0,0 +1,255 @@
+/**
+ * spork/clientpool.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+var ErrPoolClosed = errors.New("client pool closed")
+
+// healthCheckTimeout bounds the Ping of a pooled client due for a health check
+const healthCheckTimeout = 5 * time.Second
+
+// detachedContext keeps the values of its parent, like the outgoing metadata, without its deadline and cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// pooledClient is a flow client shared by every request to its access node
+type pooledClient struct {
+	flowClient *client.Client
+
+	refs int
+
+	lastUsed time.Time
+
+	lastChecked time.Time
+
+	// discarded clients are closed once the last user releases them
+	discarded bool
+}
+
+// ClientPool keeps one flow client per access node, shared by all requests.
+// Clients unused for idleTimeout are closed, clients not checked for healthCheckInterval are pinged before use.
+type ClientPool struct {
+	sync.Mutex
+
+	idleTimeout time.Duration
+
+	healthCheckInterval time.Duration
+
+	dialOptions []grpc.DialOption
+
+	clients map[string]*pooledClient
+
+	// draining are the discarded clients still in use
+	draining map[*client.Client]*pooledClient
+
+	closed bool
+
+	done chan struct{}
+}
+
+func NewClientPool(idleTimeout time.Duration, healthCheckInterval time.Duration, dialOptions ...grpc.DialOption) *ClientPool {
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithInsecure(), grpc.WithMaxMsgSize(140e6)}
+	}
+	pool := &ClientPool{
+		idleTimeout:         idleTimeout,
+		healthCheckInterval: healthCheckInterval,
+		dialOptions:         dialOptions,
+		clients:             make(map[string]*pooledClient),
+		draining:            make(map[*client.Client]*pooledClient),
+		done:                make(chan struct{}),
+	}
+	if idleTimeout > 0 {
+		go pool.run()
+	}
+	return pool
+}
+
+// run evicts the idle clients until the pool is closed
+func (pool *ClientPool) run() {
+	ticker := time.NewTicker(pool.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pool.done:
+			return
+		case now := <-ticker.C:
+			pool.evictIdle(now)
+		}
+	}
+}
+
+func (pool *ClientPool) evictIdle(now time.Time) {
+	pool.Lock()
+	defer pool.Unlock()
+	for accessNode, pooled := range pool.clients {
+		if pooled.refs == 0 && now.Sub(pooled.lastUsed) >= pool.idleTimeout {
+			log.Info("ClientPool: close idle client ", accessNode)
+			pooled.flowClient.Close()
+			delete(pool.clients, accessNode)
+		}
+	}
+}
+
+// Get returns the client of accessNode, dialing it when needed. Every Get must be paired with a Release.
+// The health check keeps the values of ctx but not its deadline, a cancelled request does not drop a healthy client.
+func (pool *ClientPool) Get(ctx context.Context, accessNode string) (*client.Client, error) {
+	pooled, needsCheck, err := pool.acquire(accessNode)
+	if err != nil {
+		return nil, err
+	}
+	if needsCheck {
+		pingCtx, cancel := context.WithTimeout(detachedContext{ctx}, healthCheckTimeout)
+		err := pooled.flowClient.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			log.Error("ClientPool: client of ", accessNode, " is not healthy, redialing: ", err)
+			pool.Discard(accessNode, pooled.flowClient)
+			pool.Release(accessNode, pooled.flowClient)
+			pooled, _, err = pool.acquire(accessNode)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pooled.flowClient, nil
+}
+
+// acquire takes a reference on the client of accessNode and reports whether it is due for a health check
+func (pool *ClientPool) acquire(accessNode string) (*pooledClient, bool, error) {
+	pool.Lock()
+	defer pool.Unlock()
+	if pool.closed {
+		return nil, false, ErrPoolClosed
+	}
+
+	now := time.Now()
+	needsCheck := false
+	pooled, ok := pool.clients[accessNode]
+	if ok {
+		needsCheck = now.Sub(pooled.lastChecked) >= pool.healthCheckInterval
+		if needsCheck {
+			pooled.lastChecked = now
+		}
+	} else {
+		flowClient, err := client.New(accessNode, pool.dialOptions...)
+		if err != nil {
+			return nil, false, err
+		}
+		pooled = &pooledClient{flowClient: flowClient, lastChecked: now}
+		pool.clients[accessNode] = pooled
+	}
+	pooled.refs++
+	pooled.lastUsed = now
+	return pooled, needsCheck, nil
+}
+
+// find returns the pooled entry holding flowClient
+func (pool *ClientPool) find(accessNode string, flowClient *client.Client) *pooledClient {
+	if pooled, ok := pool.clients[accessNode]; ok && pooled.flowClient == flowClient {
+		return pooled
+	}
+	return nil
+}
+
+// Release returns a client obtained by Get
+func (pool *ClientPool) Release(accessNode string, flowClient *client.Client) {
+	pool.Lock()
+	defer pool.Unlock()
+	pooled := pool.find(accessNode, flowClient)
+	if pooled == nil {
+		pooled = pool.draining[flowClient]
+		if pooled == nil {
+			return
+		}
+	}
+	pooled.refs--
+	pooled.lastUsed = time.Now()
+	if pooled.discarded && pooled.refs <= 0 {
+		pooled.flowClient.Close()
+		delete(pool.draining, flowClient)
+	}
+}
+
+// Discard drops the client of accessNode so the next Get dials a new connection.
+// Requests still holding it keep using it until they release it.
+func (pool *ClientPool) Discard(accessNode string, flowClient *client.Client) {
+	pool.Lock()
+	defer pool.Unlock()
+	pooled := pool.find(accessNode, flowClient)
+	if pooled == nil {
+		return
+	}
+	delete(pool.clients, accessNode)
+	if pooled.refs <= 0 {
+		pooled.flowClient.Close()
+		return
+	}
+	pooled.discarded = true
+	pool.draining[flowClient] = pooled
+}
+
+// Len returns the number of pooled access nodes
+func (pool *ClientPool) Len() int {
+	pool.Lock()
+	defer pool.Unlock()
+	return len(pool.clients)
+}
+
+// Close closes every pooled client
+func (pool *ClientPool) Close() error {
+	pool.Lock()
+	defer pool.Unlock()
+	if pool.closed {
+		return nil
+	}
+	pool.closed = true
+	close(pool.done)
+	for accessNode, pooled := range pool.clients {
+		pooled.flowClient.Close()
+		delete(pool.clients, accessNode)
+	}
+	for flowClient := range pool.draining {
+		flowClient.Close()
+		delete(pool.draining, flowClient)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package spork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPoolReusesClients(t *testing.T) {
+	addr := newFakeAccessNodeAddr(t, &fakeAccessNode{})
+	pool := NewClientPool(time.Minute, time.Minute)
+	defer pool.Close()
+
+	first, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	second, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, 1, pool.Len())
+
+	pool.Release(addr, first)
+	pool.Release(addr, second)
+	require.Nil(t, first.Ping(context.Background()), "released clients stay open")
+}
+
+func TestClientPoolEvictsIdleClients(t *testing.T) {
+	addr := newFakeAccessNodeAddr(t, &fakeAccessNode{})
+	pool := NewClientPool(time.Minute, time.Minute)
+	defer pool.Close()
+
+	busy, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.evictIdle(time.Now().Add(time.Hour))
+	require.Equal(t, 1, pool.Len(), "clients in use are not evicted")
+
+	pool.Release(addr, busy)
+	pool.evictIdle(time.Now().Add(30 * time.Second))
+	require.Equal(t, 1, pool.Len())
+	pool.evictIdle(time.Now().Add(time.Hour))
+	require.Equal(t, 0, pool.Len())
+
+	fresh, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	defer pool.Release(addr, fresh)
+	require.NotSame(t, busy, fresh)
+}
+
+func TestClientPoolHealthCheck(t *testing.T) {
+	node := &fakeAccessNode{}
+	addr := newFakeAccessNodeAddr(t, node)
+	pool := NewClientPool(time.Minute, 0)
+	defer pool.Close()
+
+	flowClient, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.Release(addr, flowClient)
+
+	// a healthy client passes the check and is kept
+	same, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.Release(addr, same)
+	require.Same(t, flowClient, same)
+
+	// a discarded client keeps working for its users and is replaced for the next Get
+	held, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.Discard(addr, held)
+	replaced, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	require.NotSame(t, held, replaced)
+	require.Nil(t, held.Ping(context.Background()))
+	pool.Release(addr, held)
+	pool.Release(addr, replaced)
+}
+
+func TestClientPoolHealthCheckOutlivesRequest(t *testing.T) {
+	addr := newFakeAccessNodeAddr(t, &fakeAccessNode{})
+	pool := NewClientPool(time.Minute, 0)
+	defer pool.Close()
+
+	flowClient, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.Release(addr, flowClient)
+
+	// the request is gone but the client is healthy, it is kept
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	same, err := pool.Get(ctx, addr)
+	require.Nil(t, err)
+	defer pool.Release(addr, same)
+	require.Same(t, flowClient, same)
+}
+
+func TestClientPoolRedialsUnhealthyClients(t *testing.T) {
+	addr := newFakeAccessNodeAddr(t, &fakeAccessNode{})
+	pool := NewClientPool(time.Minute, 0)
+	defer pool.Close()
+
+	flowClient, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	pool.Release(addr, flowClient)
+	// a closed connection fails its ping
+	flowClient.Close()
+
+	redialed, err := pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	defer pool.Release(addr, redialed)
+	require.NotSame(t, flowClient, redialed)
+	require.Nil(t, redialed.Ping(context.Background()))
+}
+
+func TestClientPoolClose(t *testing.T) {
+	pool := NewClientPool(time.Minute, time.Minute)
+	require.Nil(t, pool.Close())
+	_, err := pool.Get(context.Background(), "localhost:9000")
+	require.Equal(t, ErrPoolClosed, err)
+}
@@ -0,0 +1,204 @@
+/**
+ * spork/enrich.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// enrichConcurrency bounds the block and transaction lookups running at once for one request
+const enrichConcurrency = 8
+
+// TransactionInfo is the transaction context attached to enriched events
+type TransactionInfo struct {
+	Payer flow.Address
+
+	Proposer flow.Address
+
+	Authorizers []flow.Address
+
+	Status flow.TransactionStatus
+
+	// ErrorMessage is set when the transaction reverted
+	ErrorMessage string
+}
+
+// NewTransactionInfo keeps the context of tx and of its result
+func NewTransactionInfo(tx *flow.Transaction, result *flow.TransactionResult) *TransactionInfo {
+	info := &TransactionInfo{
+		Payer:       tx.Payer,
+		Proposer:    tx.ProposalKey.Address,
+		Authorizers: tx.Authorizers,
+		Status:      result.Status,
+	}
+	if result.Error != nil {
+		info.ErrorMessage = result.Error.Error()
+	}
+	return info
+}
+
+// Enricher looks up the blocks and transactions of events. It lives for one request:
+// every block and transaction is fetched once, whatever the number of its events.
+type Enricher struct {
+	sync.Mutex
+
+	flowClient FlowClient
+
+	blocks map[uint64]*flow.BlockHeader
+
+	transactions map[flow.Identifier]*TransactionInfo
+}
+
+func NewEnricher(flowClient FlowClient) *Enricher {
+	return &Enricher{
+		flowClient:   flowClient,
+		blocks:       make(map[uint64]*flow.BlockHeader),
+		transactions: make(map[flow.Identifier]*TransactionInfo),
+	}
+}
+
+// Fetch looks up the blocks and transactions of blockEvents not fetched yet
+func (enricher *Enricher) Fetch(ctx context.Context, blockEvents []client.BlockEvents) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	sem := make(chan struct{}, enrichConcurrency)
+	run := func(lookup func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			if err := lookup(); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+
+	enricher.Lock()
+	for _, blockEvent := range blockEvents {
+		if len(blockEvent.Events) == 0 {
+			continue
+		}
+		height := blockEvent.Height
+		if _, ok := enricher.blocks[height]; !ok {
+			// reserve the entry so the block is looked up once
+			enricher.blocks[height] = nil
+			run(func() error {
+				header, err := enricher.flowClient.QueryBlockHeaderByHeight(ctx, height)
+				if err != nil {
+					return err
+				}
+				enricher.Lock()
+				enricher.blocks[height] = header
+				enricher.Unlock()
+				return nil
+			})
+		}
+		for _, event := range blockEvent.Events {
+			txID := event.TransactionID
+			if _, ok := enricher.transactions[txID]; ok {
+				continue
+			}
+			enricher.transactions[txID] = nil
+			run(func() error {
+				info, err := enricher.flowClient.QueryTransaction(ctx, height, txID)
+				if err != nil {
+					return err
+				}
+				enricher.Lock()
+				enricher.transactions[txID] = info
+				enricher.Unlock()
+				return nil
+			})
+		}
+	}
+	enricher.Unlock()
+
+	wg.Wait()
+	if firstErr == nil {
+		// lookups skipped because the request is gone
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		// drop the reserved entries so a later Fetch tries again
+		enricher.Lock()
+		for height, header := range enricher.blocks {
+			if header == nil {
+				delete(enricher.blocks, height)
+			}
+		}
+		for txID, info := range enricher.transactions {
+			if info == nil {
+				delete(enricher.transactions, txID)
+			}
+		}
+		enricher.Unlock()
+		return firstErr
+	}
+	return nil
+}
+
+// contexts returns the block and transaction context of an event fetched before, nil for the ones not fetched
+func (enricher *Enricher) contexts(height uint64, txID flow.Identifier) (*pb.BlockContext, *pb.TransactionContext) {
+	enricher.Lock()
+	defer enricher.Unlock()
+	var blockContext *pb.BlockContext
+	if header := enricher.blocks[height]; header != nil {
+		blockContext = &pb.BlockContext{
+			Id:       header.ID.String(),
+			ParentId: header.ParentID.String(),
+		}
+	}
+	var transactionContext *pb.TransactionContext
+	if info := enricher.transactions[txID]; info != nil {
+		authorizers := make([]string, 0, len(info.Authorizers))
+		for _, authorizer := range info.Authorizers {
+			authorizers = append(authorizers, addressToJSON(authorizer))
+		}
+		transactionContext = &pb.TransactionContext{
+			Payer:        addressToJSON(info.Payer),
+			Proposer:     addressToJSON(info.Proposer),
+			Authorizers:  authorizers,
+			Status:       info.Status.String(),
+			ErrorMessage: info.ErrorMessage,
+		}
+	}
+	return blockContext, transactionContext
+}
+
+// addressToJSON prints an address the way Cadence prints address values
+func addressToJSON(address flow.Address) string {
+	return "0x" + address.Hex()
+}
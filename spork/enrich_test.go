@@ -0,0 +1,132 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+)
+
+// enrichFlowClient counts the block and transaction lookups, transactions in failTx fail
+type enrichFlowClient struct {
+	headFlowClient
+
+	lookups sync.Mutex
+
+	headerCalls int
+
+	txCalls int
+
+	failTx flow.Identifier
+}
+
+func (f *enrichFlowClient) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	f.lookups.Lock()
+	defer f.lookups.Unlock()
+	f.headerCalls++
+	return &flow.BlockHeader{
+		ID:        flow.BytesToID([]byte{byte(height)}),
+		ParentID:  flow.BytesToID([]byte{byte(height - 1)}),
+		Height:    height,
+		Timestamp: time.Unix(int64(height), 0),
+	}, nil
+}
+
+func (f *enrichFlowClient) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error) {
+	f.lookups.Lock()
+	defer f.lookups.Unlock()
+	f.txCalls++
+	if txID == f.failTx {
+		return nil, errors.New("transaction not found")
+	}
+	return &TransactionInfo{
+		Payer:       flow.HexToAddress("01"),
+		Proposer:    flow.HexToAddress("03"),
+		Authorizers: []flow.Address{flow.HexToAddress("02"), flow.HexToAddress("04")},
+		Status:      flow.TransactionStatusSealed,
+	}, nil
+}
+
+func enrichTestEvent(txID flow.Identifier, eventIndex int) flow.Event {
+	event := newTestDepositEvent(0)
+	event.TransactionID = txID
+	event.EventIndex = eventIndex
+	return event
+}
+
+// enrichTestBlockEvents spreads two events of each of txA and txB over blocks 10 to 12
+func enrichTestBlockEvents(txA flow.Identifier, txB flow.Identifier) []client.BlockEvents {
+	return []client.BlockEvents{
+		{Height: 10, Events: []flow.Event{enrichTestEvent(txA, 0), enrichTestEvent(txA, 1)}},
+		{Height: 11, Events: []flow.Event{enrichTestEvent(txB, 0)}},
+		{Height: 12, Events: []flow.Event{enrichTestEvent(txB, 1)}},
+		{Height: 13},
+	}
+}
+
+func TestEnricherFetchesOnce(t *testing.T) {
+	flowClient := &enrichFlowClient{}
+	txA := flow.BytesToID([]byte{0xa})
+	txB := flow.BytesToID([]byte{0xb})
+	blockEvents := enrichTestBlockEvents(txA, txB)
+
+	enricher := NewEnricher(flowClient)
+	require.Nil(t, enricher.Fetch(context.Background(), blockEvents))
+	require.Nil(t, enricher.Fetch(context.Background(), blockEvents))
+	require.Equal(t, 3, flowClient.headerCalls, "a block without events is not looked up")
+	require.Equal(t, 2, flowClient.txCalls)
+
+	events := BlockEventsToJSONWithOptions(blockEvents, EventJSONOptions{Enricher: enricher})
+	require.Len(t, events, 4)
+	require.Equal(t, flow.BytesToID([]byte{10}).String(), events[0].Block.Id)
+	require.Equal(t, flow.BytesToID([]byte{9}).String(), events[0].Block.ParentId)
+	require.Equal(t, "0x0000000000000001", events[0].Transaction.Payer)
+	require.Equal(t, "0x0000000000000003", events[0].Transaction.Proposer)
+	require.Equal(t, []string{"0x0000000000000002", "0x0000000000000004"}, events[0].Transaction.Authorizers)
+	require.Equal(t, "SEALED", events[0].Transaction.Status)
+
+	require.Nil(t, BlockEventsToJSON(blockEvents)[0].Transaction, "events are only enriched on request")
+}
+
+func TestEnricherError(t *testing.T) {
+	txA := flow.BytesToID([]byte{0xa})
+	txB := flow.BytesToID([]byte{0xb})
+	flowClient := &enrichFlowClient{failTx: txB}
+	blockEvents := enrichTestBlockEvents(txA, txB)
+
+	enricher := NewEnricher(flowClient)
+	require.EqualError(t, enricher.Fetch(context.Background(), blockEvents), "transaction not found")
+
+	// the failed lookup is made again by the next fetch
+	flowClient.failTx = flow.EmptyID
+	require.Nil(t, enricher.Fetch(context.Background(), blockEvents))
+	events := BlockEventsToJSONWithOptions(blockEvents, EventJSONOptions{Enricher: enricher})
+	require.NotNil(t, events[3].Transaction)
+}
+
+func TestSporkStoreQueryTransaction(t *testing.T) {
+	first := &fakeAccessNode{head: 149}
+	second := &fakeAccessNode{head: 300}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first))
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss.SporkList = append(ss.SporkList, Spork{Name: "spork2", RootHeight: 150, AccessNode: secondAddr, AccessNodes: []string{secondAddr}})
+
+	// the transaction of a historical block is read from the nodes of its spork
+	info, err := ss.QueryTransaction(context.Background(), 120, flow.BytesToID([]byte{0x02}))
+	require.Nil(t, err)
+	require.Equal(t, flow.HexToAddress("01"), info.Payer)
+	require.Equal(t, flow.HexToAddress("01"), info.Proposer)
+	require.Equal(t, []flow.Address{flow.HexToAddress("02")}, info.Authorizers)
+	require.Equal(t, flow.TransactionStatusSealed, info.Status)
+	first.Lock()
+	require.Equal(t, 1, first.txCalls)
+	first.Unlock()
+	second.Lock()
+	require.Zero(t, second.txCalls)
+	second.Unlock()
+}
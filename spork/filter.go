@@ -0,0 +1,271 @@
+/**
+ * spork/filter.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// ErrInvalidFilter is returned for a filter expression that cannot be evaluated
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// fieldCondition reports whether the value of a field satisfies one operator
+type fieldCondition func(value cadence.Value) bool
+
+// EventFilter is a compiled pb.EventFilter, see NewEventFilter
+type EventFilter struct {
+	field string
+
+	conditions []fieldCondition
+
+	and []*EventFilter
+
+	or []*EventFilter
+}
+
+// NewEventFilter compiles filter, a nil filter gives a nil EventFilter which matches every event
+func NewEventFilter(filter *pb.EventFilter) (*EventFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	compiled := &EventFilter{field: filter.Field}
+
+	if filter.Eq != "" {
+		compiled.conditions = append(compiled.conditions, equalCondition([]string{filter.Eq}))
+	}
+	if len(filter.In) > 0 {
+		compiled.conditions = append(compiled.conditions, equalCondition(filter.In))
+	}
+	if filter.Gte != "" {
+		bound, ok := new(big.Rat).SetString(filter.Gte)
+		if !ok {
+			return nil, fmt.Errorf("%w: gte %q is not a number", ErrInvalidFilter, filter.Gte)
+		}
+		compiled.conditions = append(compiled.conditions, func(value cadence.Value) bool {
+			number := numberOf(value)
+			return number != nil && number.Cmp(bound) >= 0
+		})
+	}
+	if filter.Lte != "" {
+		bound, ok := new(big.Rat).SetString(filter.Lte)
+		if !ok {
+			return nil, fmt.Errorf("%w: lte %q is not a number", ErrInvalidFilter, filter.Lte)
+		}
+		compiled.conditions = append(compiled.conditions, func(value cadence.Value) bool {
+			number := numberOf(value)
+			return number != nil && number.Cmp(bound) <= 0
+		})
+	}
+	if filter.Prefix != "" {
+		prefix := filter.Prefix
+		compiled.conditions = append(compiled.conditions, func(value cadence.Value) bool {
+			text, ok := textOf(value)
+			if _, isAddress := value.(cadence.Address); isAddress {
+				return ok && strings.HasPrefix(text, strings.ToLower(prefix))
+			}
+			return ok && strings.HasPrefix(text, prefix)
+		})
+	}
+	if filter.Field == "" && len(compiled.conditions) > 0 {
+		return nil, fmt.Errorf("%w: an operator needs a field", ErrInvalidFilter)
+	}
+	if filter.Field != "" && len(compiled.conditions) == 0 {
+		return nil, fmt.Errorf("%w: field %s has no operator", ErrInvalidFilter, filter.Field)
+	}
+
+	for _, child := range filter.And {
+		if child == nil {
+			return nil, fmt.Errorf("%w: empty and", ErrInvalidFilter)
+		}
+		and, err := NewEventFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		compiled.and = append(compiled.and, and)
+	}
+	for _, child := range filter.Or {
+		if child == nil {
+			return nil, fmt.Errorf("%w: empty or", ErrInvalidFilter)
+		}
+		or, err := NewEventFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		compiled.or = append(compiled.or, or)
+	}
+	if filter.Field == "" && len(compiled.and) == 0 && len(compiled.or) == 0 {
+		return nil, fmt.Errorf("%w: empty filter", ErrInvalidFilter)
+	}
+	return compiled, nil
+}
+
+// Match reports whether the fields of event satisfy the filter, a missing field never does
+func (filter *EventFilter) Match(event *cadence.Event) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.field != "" {
+		value, ok := eventField(event, filter.field)
+		if !ok {
+			return false
+		}
+		for _, condition := range filter.conditions {
+			if !condition(value) {
+				return false
+			}
+		}
+	}
+	for _, and := range filter.and {
+		if !and.Match(event) {
+			return false
+		}
+	}
+	if len(filter.or) == 0 {
+		return true
+	}
+	for _, or := range filter.or {
+		if or.Match(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterBlockEvents keeps the events matching filter, blocks left without events are dropped
+func FilterBlockEvents(blockEvents []client.BlockEvents, filter *EventFilter) []client.BlockEvents {
+	if filter == nil {
+		return blockEvents
+	}
+	result := make([]client.BlockEvents, 0, len(blockEvents))
+	for _, blockEvent := range blockEvents {
+		events := make([]flow.Event, 0, len(blockEvent.Events))
+		for _, event := range blockEvent.Events {
+			if filter.Match(&event.Value) {
+				events = append(events, event)
+			}
+		}
+		if len(events) > 0 {
+			blockEvent.Events = events
+			result = append(result, blockEvent)
+		}
+	}
+	return result
+}
+
+// eventField returns the value of the field named name, optionals are unwrapped and nil counts as missing
+func eventField(event *cadence.Event, name string) (cadence.Value, bool) {
+	if event.EventType == nil {
+		return nil, false
+	}
+	for i, field := range event.EventType.Fields {
+		if field.Identifier != name || i >= len(event.Fields) {
+			continue
+		}
+		value := event.Fields[i]
+		for {
+			optional, ok := value.(cadence.Optional)
+			if !ok {
+				break
+			}
+			if optional.Value == nil {
+				return nil, false
+			}
+			value = optional.Value
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// equalCondition holds when the value equals one of operands
+func equalCondition(operands []string) fieldCondition {
+	return func(value cadence.Value) bool {
+		for _, operand := range operands {
+			if equalValue(value, operand) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func equalValue(value cadence.Value, operand string) bool {
+	switch v := value.(type) {
+	case cadence.Address:
+		address, ok := addressOf(operand)
+		return ok && flow.Address(v) == address
+	case cadence.String:
+		return string(v) == operand
+	}
+	if number := numberOf(value); number != nil {
+		other, ok := new(big.Rat).SetString(operand)
+		return ok && number.Cmp(other) == 0
+	}
+	text, ok := textOf(value)
+	return ok && text == operand
+}
+
+// addressOf parses a hex address with or without 0x, short addresses are padded like flow.HexToAddress does
+func addressOf(operand string) (flow.Address, bool) {
+	trimmed := strings.TrimPrefix(strings.ToLower(operand), "0x")
+	if len(trimmed)%2 == 1 {
+		trimmed = "0" + trimmed
+	}
+	b, err := hex.DecodeString(trimmed)
+	if err != nil || len(b) > flow.AddressLength {
+		return flow.Address{}, false
+	}
+	return flow.BytesToAddress(b), true
+}
+
+// numberOf returns the value of a numeric field, nil for any other one
+func numberOf(value cadence.Value) *big.Rat {
+	switch value.(type) {
+	case cadence.String, cadence.Address, cadence.Bool:
+		return nil
+	}
+	number, ok := new(big.Rat).SetString(value.String())
+	if !ok {
+		return nil
+	}
+	return number
+}
+
+// textOf returns the field as text, strings without their quotes and addresses as lowercase hex with 0x.
+// Arrays, dictionaries and composites have no text.
+func textOf(value cadence.Value) (string, bool) {
+	switch v := value.(type) {
+	case cadence.String:
+		return string(v), true
+	case cadence.Address:
+		return "0x" + strings.ToLower(v.Hex()), true
+	case cadence.Array, cadence.Dictionary, cadence.Struct, cadence.Resource, cadence.Event, cadence.Contract, cadence.Enum:
+		return "", false
+	}
+	return value.String(), true
+}
@@ -0,0 +1,113 @@
+package spork
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// newFilterTestEvent is a TokensDeposited event of amount to the optional address to
+func newFilterTestEvent(amount string, to *flow.Address) cadence.Event {
+	eventType := &cadence.EventType{
+		QualifiedIdentifier: "FlowToken.TokensDeposited",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+			{Identifier: "to", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+			{Identifier: "memo", Type: cadence.StringType{}},
+		},
+	}
+	value, _ := cadence.NewUFix64(amount)
+	recipient := cadence.NewOptional(nil)
+	if to != nil {
+		recipient = cadence.NewOptional(cadence.BytesToAddress(to.Bytes()))
+	}
+	return cadence.NewEvent([]cadence.Value{value, recipient, cadence.String("order-42")}).WithType(eventType)
+}
+
+func TestEventFilterMatch(t *testing.T) {
+	alice := flow.HexToAddress("1654653399040a61")
+	bob := flow.HexToAddress("f233dcee88fe0abe")
+	event := newFilterTestEvent("12.5", &alice)
+	noRecipient := newFilterTestEvent("12.5", nil)
+
+	cases := []struct {
+		filter *pb.EventFilter
+		match  bool
+	}{
+		{&pb.EventFilter{Field: "to", Eq: "0x1654653399040a61"}, true},
+		{&pb.EventFilter{Field: "to", Eq: "1654653399040A61"}, true},
+		{&pb.EventFilter{Field: "to", Eq: bob.Hex()}, false},
+		{&pb.EventFilter{Field: "to", In: []string{bob.Hex(), alice.Hex()}}, true},
+		{&pb.EventFilter{Field: "to", Prefix: "0x1654"}, true},
+		{&pb.EventFilter{Field: "amount", Eq: "12.5"}, true},
+		{&pb.EventFilter{Field: "amount", Gte: "10", Lte: "12.5"}, true},
+		{&pb.EventFilter{Field: "amount", Gte: "12.50000001"}, false},
+		{&pb.EventFilter{Field: "memo", Prefix: "order-"}, true},
+		{&pb.EventFilter{Field: "memo", Gte: "1"}, false},
+		{&pb.EventFilter{Field: "missing", Eq: "1"}, false},
+		{&pb.EventFilter{And: []*pb.EventFilter{
+			{Field: "to", Eq: alice.Hex()},
+			{Field: "amount", Lte: "1"},
+		}}, false},
+		{&pb.EventFilter{Or: []*pb.EventFilter{
+			{Field: "to", Eq: bob.Hex()},
+			{Field: "amount", Gte: "10"},
+		}}, true},
+		{&pb.EventFilter{Field: "memo", Eq: "order-42", Or: []*pb.EventFilter{
+			{Field: "to", Eq: bob.Hex()},
+		}}, false},
+	}
+	for _, c := range cases {
+		filter, err := NewEventFilter(c.filter)
+		require.Nil(t, err, c.filter.String())
+		require.Equal(t, c.match, filter.Match(&event), c.filter.String())
+	}
+
+	filter, err := NewEventFilter(&pb.EventFilter{Field: "to", Eq: alice.Hex()})
+	require.Nil(t, err)
+	require.False(t, filter.Match(&noRecipient), "a nil optional never matches")
+
+	filter, err = NewEventFilter(nil)
+	require.Nil(t, err)
+	require.True(t, filter.Match(&event))
+}
+
+func TestNewEventFilterInvalid(t *testing.T) {
+	for _, filter := range []*pb.EventFilter{
+		{},
+		{Field: "to"},
+		{Eq: "1"},
+		{Field: "amount", Gte: "ten"},
+		{Field: "amount", Lte: "1..0"},
+		{Or: []*pb.EventFilter{{Field: "to"}}},
+	} {
+		_, err := NewEventFilter(filter)
+		require.ErrorIs(t, err, ErrInvalidFilter, filter.String())
+	}
+}
+
+func TestFilterBlockEvents(t *testing.T) {
+	alice := flow.HexToAddress("1654653399040a61")
+	bob := flow.HexToAddress("f233dcee88fe0abe")
+	blockEvents := []client.BlockEvents{
+		{Height: 1, Events: []flow.Event{{Value: newFilterTestEvent("1.0", &alice)}, {Value: newFilterTestEvent("2.0", &bob)}}},
+		{Height: 2, Events: []flow.Event{{Value: newFilterTestEvent("3.0", &bob)}}},
+		{Height: 3, Events: []flow.Event{{Value: newFilterTestEvent("4.0", &alice)}}},
+	}
+	filter, err := NewEventFilter(&pb.EventFilter{Field: "to", Eq: alice.Hex()})
+	require.Nil(t, err)
+
+	filtered := FilterBlockEvents(blockEvents, filter)
+	require.Len(t, filtered, 2)
+	require.Equal(t, uint64(1), filtered[0].Height)
+	require.Len(t, filtered[0].Events, 1)
+	require.Equal(t, uint64(3), filtered[1].Height)
+	require.Len(t, blockEvents[0].Events, 2, "the input is left untouched")
+
+	require.Equal(t, blockEvents, FilterBlockEvents(blockEvents, nil))
+}
@@ -0,0 +1,143 @@
+/**
+ * spork/page.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+)
+
+// ErrInvalidCursor is returned for a cursor not made by EventCursor.String
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// errPageFull stops the stream of a page once the next event is known
+var errPageFull = errors.New("page is full")
+
+// EventCursor is the position of an event, events are ordered by height, transaction index and event index.
+// The position is the same on every access node, so a cursor stays valid across sporks.
+type EventCursor struct {
+	Height uint64
+
+	TransactionIndex int
+
+	EventIndex int
+}
+
+// Before reports whether the event at cursor comes before the one at other
+func (cursor EventCursor) Before(other EventCursor) bool {
+	if cursor.Height != other.Height {
+		return cursor.Height < other.Height
+	}
+	if cursor.TransactionIndex != other.TransactionIndex {
+		return cursor.TransactionIndex < other.TransactionIndex
+	}
+	return cursor.EventIndex < other.EventIndex
+}
+
+// String encodes the cursor as an opaque token
+func (cursor EventCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%d", cursor.Height, cursor.TransactionIndex, cursor.EventIndex)))
+}
+
+// ParseEventCursor decodes a token returned by EventCursor.String
+func ParseEventCursor(token string) (EventCursor, error) {
+	var cursor EventCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	var rest string
+	n, _ := fmt.Sscanf(string(raw), "%d:%d:%d%s", &cursor.Height, &cursor.TransactionIndex, &cursor.EventIndex, &rest)
+	if n != 3 || cursor.TransactionIndex < 0 || cursor.EventIndex < 0 {
+		return cursor, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// EventPage is a page of at most limit events
+type EventPage struct {
+	BlockEvents []client.BlockEvents
+
+	// NextCursor resumes after the last event of the page, empty when the range is done
+	NextCursor string
+}
+
+// QueryEventPage returns the first limit events of [start, end] matching filter after cursor, an empty cursor starts at start.
+// Only the batches needed to fill the page are fetched.
+func QueryEventPage(ctx context.Context, flowClient FlowClient, eventTypes []string, filter *EventFilter, start uint64, end uint64, limit int, cursor string) (*EventPage, error) {
+	page := &EventPage{BlockEvents: make([]client.BlockEvents, 0)}
+
+	var after *EventCursor
+	if cursor != "" {
+		parsed, err := ParseEventCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.Height > end {
+			return page, nil
+		}
+		if parsed.Height > start {
+			start = parsed.Height
+		}
+		after = &parsed
+	}
+
+	count := 0
+	var last EventCursor
+	err := flowClient.StreamEventByBlockRange(ctx, eventTypes, start, end, func(_ uint64, _ uint64, blockEvents []client.BlockEvents) error {
+		for _, blockEvent := range MergeBlockEvents(blockEvents) {
+			events := make([]flow.Event, 0, len(blockEvent.Events))
+			full := false
+			for _, event := range blockEvent.Events {
+				position := EventCursor{Height: blockEvent.Height, TransactionIndex: event.TransactionIndex, EventIndex: event.EventIndex}
+				if after != nil && !after.Before(position) {
+					continue
+				}
+				if !filter.Match(&event.Value) {
+					continue
+				}
+				// one more event exists, the page ends at the last one taken
+				if limit > 0 && count == limit {
+					full = true
+					break
+				}
+				events = append(events, event)
+				count++
+				last = position
+			}
+			if len(events) > 0 {
+				blockEvent.Events = events
+				page.BlockEvents = append(page.BlockEvents, blockEvent)
+			}
+			if full {
+				page.NextCursor = last.String()
+				return errPageFull
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPageFull) {
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,106 @@
+package spork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+)
+
+// collectPages walks every page of [start, end] and returns the events with the number of pages
+func collectPages(t *testing.T, flowClient FlowClient, eventTypes []string, start uint64, end uint64, limit int) ([]EventCursor, int) {
+	positions := make([]EventCursor, 0)
+	pages := 0
+	cursor := ""
+	for {
+		page, err := QueryEventPage(context.Background(), flowClient, eventTypes, nil, start, end, limit, cursor)
+		require.Nil(t, err)
+		pages++
+		count := 0
+		for _, blockEvent := range page.BlockEvents {
+			for _, event := range blockEvent.Events {
+				positions = append(positions, EventCursor{Height: blockEvent.Height, TransactionIndex: event.TransactionIndex, EventIndex: event.EventIndex})
+				count++
+			}
+		}
+		require.LessOrEqual(t, count, limit)
+		if page.NextCursor == "" {
+			return positions, pages
+		}
+		require.Equal(t, limit, count, "only the last page may be short")
+		cursor = page.NextCursor
+	}
+}
+
+func TestEventCursorRoundTrip(t *testing.T) {
+	cursor := EventCursor{Height: 19050753, TransactionIndex: 3, EventIndex: 12}
+	parsed, err := ParseEventCursor(cursor.String())
+	require.Nil(t, err)
+	require.Equal(t, cursor, parsed)
+
+	for _, token := range []string{"not base64!", "MTI", cursor.String() + "AA", "MTI6LTE6Mw"} {
+		_, err := ParseEventCursor(token)
+		require.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestQueryEventPageWalksRange(t *testing.T) {
+	flowClient := &headFlowClient{}
+	eventTypes := []string{"A.0000000000000001.Test.A", "A.0000000000000001.Test.B"}
+
+	for _, limit := range []int{3, 4, 100} {
+		positions, pages := collectPages(t, flowClient, eventTypes, 10, 19, limit)
+		require.Len(t, positions, 20, "limit %d", limit)
+		require.Equal(t, (20+limit-1)/limit, pages, "limit %d", limit)
+		for i := 1; i < len(positions); i++ {
+			require.True(t, positions[i-1].Before(positions[i]), "events out of order at %d", i)
+		}
+	}
+}
+
+func TestQueryEventPageInvalidCursor(t *testing.T) {
+	_, err := QueryEventPage(context.Background(), &headFlowClient{}, []string{cacheTestEvent}, nil, 0, 10, 5, "garbage")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestQueryEventPageCursorPastEnd(t *testing.T) {
+	cursor := EventCursor{Height: 50}
+	page, err := QueryEventPage(context.Background(), &headFlowClient{}, []string{cacheTestEvent}, nil, 0, 10, 5, cursor.String())
+	require.Nil(t, err)
+	require.Empty(t, page.BlockEvents)
+	require.Empty(t, page.NextCursor)
+}
+
+func TestQueryEventPageAcrossSporks(t *testing.T) {
+	first := &fakeAccessNode{}
+	second := &fakeAccessNode{}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first))
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss.SporkList = append(ss.SporkList, Spork{Name: "spork2", RootHeight: 150, AccessNode: secondAddr, AccessNodes: []string{secondAddr}})
+
+	// the fake nodes emit one event every 5 blocks
+	positions, pages := collectPages(t, ss, []string{cacheTestEvent}, 100, 199, 7)
+	require.Len(t, positions, 20)
+	require.Equal(t, 3, pages)
+	for i, position := range positions {
+		require.Equal(t, uint64(100+5*i), position.Height)
+	}
+	require.Greater(t, first.callCount(), 0)
+	require.Greater(t, second.callCount(), 0)
+
+	// a page ending in the first spork resumes in the second one
+	cursor := EventCursor{Height: 145}
+	page, err := QueryEventPage(context.Background(), ss, []string{cacheTestEvent}, nil, 100, 199, 2, cursor.String())
+	require.Nil(t, err)
+	require.Equal(t, []uint64{150, 155}, pageHeights(page.BlockEvents))
+	require.NotEmpty(t, page.NextCursor)
+}
+
+func pageHeights(blockEvents []client.BlockEvents) []uint64 {
+	heights := make([]uint64, 0, len(blockEvents))
+	for _, blockEvent := range blockEvents {
+		heights = append(heights, blockEvent.Height)
+	}
+	return heights
+}
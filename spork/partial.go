@@ -0,0 +1,110 @@
+/**
+ * spork/partial.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+// FailedRange is a block range [Start, End] that could not be fetched
+type FailedRange struct {
+	Start uint64
+
+	End uint64
+
+	Err error
+}
+
+// PartialError is returned in partial mode together with the events that were fetched,
+// it lists the block ranges missing from them
+type PartialError struct {
+	FailedRanges []FailedRange
+}
+
+func (e *PartialError) Error() string {
+	if len(e.FailedRanges) == 1 {
+		failed := e.FailedRanges[0]
+		return fmt.Sprintf("failed to fetch blocks %d - %d: %v", failed.Start, failed.End, failed.Err)
+	}
+	return fmt.Sprintf("failed to fetch %d block ranges", len(e.FailedRanges))
+}
+
+// newPartialError sorts ranges by height and drops the duplicates reported by several event types
+func newPartialError(ranges []FailedRange) *PartialError {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Start != ranges[j].Start {
+			return ranges[i].Start < ranges[j].Start
+		}
+		return ranges[i].End < ranges[j].End
+	})
+	deduped := make([]FailedRange, 0, len(ranges))
+	for _, failed := range ranges {
+		if n := len(deduped); n > 0 && deduped[n-1].Start == failed.Start && deduped[n-1].End == failed.End {
+			continue
+		}
+		deduped = append(deduped, failed)
+	}
+	return &PartialError{FailedRanges: deduped}
+}
+
+// IsPartialError reports whether err only lists missing ranges, the events returned with it are valid
+func IsPartialError(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
+
+// failedRangesOf returns the ranges of a PartialError, nil for any other error
+func failedRangesOf(err error) []FailedRange {
+	var partial *PartialError
+	if errors.As(err, &partial) {
+		return partial.FailedRanges
+	}
+	return nil
+}
+
+// FailedRangesToJSON converts the ranges of a PartialError, any other error gives an empty list
+func FailedRangesToJSON(err error) []*pb.FailedHeightRange {
+	result := make([]*pb.FailedHeightRange, 0)
+	for _, failed := range failedRangesOf(err) {
+		result = append(result, &pb.FailedHeightRange{
+			Start: failed.Start,
+			End:   failed.End,
+			Error: failed.Err.Error(),
+		})
+	}
+	return result
+}
+
+// partialResultsKey marks a context in partial mode
+type partialResultsKey struct{}
+
+// WithPartialResults makes the queries made with ctx skip the blocks they cannot fetch
+// and return a PartialError with the events, instead of failing
+func WithPartialResults(ctx context.Context, partial bool) context.Context {
+	return context.WithValue(ctx, partialResultsKey{}, partial)
+}
+
+func partialResults(ctx context.Context) bool {
+	partial, _ := ctx.Value(partialResultsKey{}).(bool)
+	return partial
+}
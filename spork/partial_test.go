@@ -0,0 +1,169 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireFailedRanges checks err is a PartialError listing exactly expected
+func requireFailedRanges(t *testing.T, expected []heightRange, err error) {
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial), "unexpected error %v", err)
+	ranges := make([]heightRange, 0, len(partial.FailedRanges))
+	for _, failed := range partial.FailedRanges {
+		require.NotNil(t, failed.Err)
+		ranges = append(ranges, heightRange{failed.Start, failed.End})
+	}
+	require.Equal(t, expected, ranges)
+}
+
+func TestForEachEventByBlockRangePartial(t *testing.T) {
+	node := &fakeAccessNode{badHeights: []uint64{130, 250}}
+	flowClient := newFakeAccessNodeClient(t, node)
+	ctx := WithRetryPolicy(context.Background(), testRetryPolicy)
+
+	_, err := IterQueryEventByBlockRange(ctx, flowClient, []string{cacheTestEvent}, 100, 299, 50)
+	require.Equal(t, codes.Internal, status.Code(err), "without partial mode the request fails")
+
+	events, err := IterQueryEventByBlockRange(WithPartialResults(ctx, true), flowClient, []string{cacheTestEvent}, 100, 299, 50)
+	requireFailedRanges(t, []heightRange{{130, 130}, {250, 250}}, err)
+	require.Len(t, events, 198)
+	for _, blockEvent := range events {
+		require.NotEqual(t, uint64(130), blockEvent.Height)
+		require.NotEqual(t, uint64(250), blockEvent.Height)
+	}
+
+	events, err = ParallelIterQueryEventByBlockRange(WithPartialResults(ctx, true), flowClient, []string{cacheTestEvent}, 100, 299, 50, 4)
+	requireFailedRanges(t, []heightRange{{130, 130}, {250, 250}}, err)
+	require.Len(t, events, 198)
+	for i := 1; i < len(events); i++ {
+		require.Greater(t, events[i].Height, events[i-1].Height)
+	}
+
+	// a rejected request still fails in partial mode
+	node = &fakeAccessNode{err: status.Error(codes.InvalidArgument, "invalid event type")}
+	_, err = IterQueryEventByBlockRange(WithPartialResults(ctx, true), newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 99, 50)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSporkStorePartial(t *testing.T) {
+	first := &fakeAccessNode{badHeights: []uint64{150}}
+	second := &fakeAccessNode{badHeights: []uint64{150}}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first), newFakeAccessNodeAddr(t, second))
+
+	ret, err := ss.QueryEventByBlockRange(WithPartialResults(context.Background(), true), []string{cacheTestEvent}, 0, 299)
+	requireFailedRanges(t, []heightRange{{150, 150}}, err)
+	require.Len(t, ret, 299)
+
+	// batches no node can serve at all are reported whole
+	ss = newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}))
+	ret, err = ss.QueryEventByBlockRange(WithPartialResults(context.Background(), true), []string{cacheTestEvent}, 0, 299)
+	requireFailedRanges(t, []heightRange{{0, 99}, {100, 199}, {200, 299}}, err)
+	require.Len(t, ret, 0)
+}
+
+func TestSporkStorePartialAcrossSporks(t *testing.T) {
+	first := &fakeAccessNode{badHeights: []uint64{50}}
+	second := &fakeAccessNode{badHeights: []uint64{180}}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first))
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss.SporkList = append(ss.SporkList, Spork{Name: "spork2", RootHeight: 100, AccessNode: secondAddr, AccessNodes: []string{secondAddr}})
+
+	// a failed range in the first spork does not end the query, the second spork is still fetched
+	ret, err := ss.QueryEventByBlockRange(WithPartialResults(context.Background(), true), []string{cacheTestEvent}, 0, 199)
+	requireFailedRanges(t, []heightRange{{50, 50}, {180, 180}}, err)
+	require.Len(t, ret, 198)
+	require.Equal(t, uint64(199), ret[len(ret)-1].Height)
+}
+
+func TestSporkCachePartial(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}, failed: []heightRange{{120, 124}}}
+	cache := newTestSporkCache(t, backend)
+
+	ret, err := cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	requireFailedRanges(t, []heightRange{{120, 124}}, err)
+	require.Len(t, ret, 9)
+
+	// only the hole is left uncached, the next query fetches just that
+	gaps, err := cache.missingRanges(cacheTestEvent, 100, 149)
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{120, 124}}, gaps)
+
+	backend.failed = nil
+	ret, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	require.Nil(t, err)
+	require.Len(t, ret, 10)
+	require.Equal(t, heightRange{120, 124}, backend.fetched[len(backend.fetched)-1])
+}
+
+func TestSporkCacheStreamPartial(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}}
+	cache := newTestSporkCache(t, backend)
+	_, err := cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 150, 179)
+	require.Nil(t, err)
+
+	backend.failed = []heightRange{{120, 124}, {145, 145}}
+	chunks := make([]heightRange, 0)
+	total := 0
+	err = cache.StreamEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 179, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		total += len(blockEvents)
+		return nil
+	})
+	requireFailedRanges(t, []heightRange{{120, 124}, {145, 145}}, err)
+	require.Equal(t, []heightRange{{100, 119}, {120, 139}, {140, 159}, {160, 179}}, chunks)
+	require.Equal(t, 14, total)
+
+	gaps, err := cache.missingRanges(cacheTestEvent, 100, 179)
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{120, 124}, {145, 145}}, gaps)
+}
+
+func TestSubtractFailedRanges(t *testing.T) {
+	gap := heightRange{100, 149}
+	require.Equal(t, []heightRange{{100, 149}}, subtractFailedRanges(gap, nil))
+	require.Equal(t, []heightRange{{100, 119}, {125, 129}, {136, 149}},
+		subtractFailedRanges(gap, []FailedRange{{Start: 130, End: 135}, {Start: 120, End: 124}, {Start: 122, End: 123}}))
+	require.Equal(t, []heightRange{{101, 148}}, subtractFailedRanges(gap, []FailedRange{{Start: 90, End: 100}, {Start: 149, End: 160}}))
+	require.Equal(t, []heightRange{}, subtractFailedRanges(gap, []FailedRange{{Start: 90, End: 160}}))
+}
+
+func TestFailedRangesToJSON(t *testing.T) {
+	require.Len(t, FailedRangesToJSON(nil), 0)
+	require.Len(t, FailedRangesToJSON(errors.New("failed")), 0)
+
+	err := newPartialError([]FailedRange{
+		{Start: 20, End: 29, Err: errors.New("node down")},
+		{Start: 10, End: 19, Err: errors.New("node down")},
+		{Start: 20, End: 29, Err: errors.New("node down")},
+	})
+	ranges := FailedRangesToJSON(err)
+	require.Len(t, ranges, 2)
+	require.Equal(t, uint64(10), ranges[0].Start)
+	require.Equal(t, uint64(19), ranges[0].End)
+	require.Equal(t, "node down", ranges[0].Error)
+	require.Equal(t, uint64(20), ranges[1].Start)
+	require.Equal(t, "failed to fetch 2 block ranges", err.Error())
+}
+
+func TestForEachBatchHandlerSeesPartialBatches(t *testing.T) {
+	fetch := func(ctx context.Context, start uint64, end uint64) ([]client.BlockEvents, error) {
+		if start == 10 {
+			return nil, newPartialError([]FailedRange{{Start: 12, End: 12, Err: errors.New("bad block")}})
+		}
+		return []client.BlockEvents{{Height: start}}, nil
+	}
+	chunks := make([]heightRange, 0)
+	err := forEachBatch(context.Background(), 0, 29, 10, 2, fetch, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		return nil
+	})
+	requireFailedRanges(t, []heightRange{{12, 12}}, err)
+	require.Equal(t, []heightRange{{0, 9}, {10, 19}, {20, 29}}, chunks)
+}
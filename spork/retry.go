@@ -0,0 +1,136 @@
+/**
+ * spork/retry.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errBatchTimeout is returned when a single access node call outlives the batch timeout
+var errBatchTimeout = errors.New("batch timeout exceeded")
+
+// RetryPolicy controls how a batch is fetched again after an error
+type RetryPolicy struct {
+	// MaxRetries bounds the retries of one batch on transient errors, 0 fails on the first one
+	MaxRetries int
+
+	// InitialBackoff is the wait before the first retry, doubled on every further retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between two retries
+	MaxBackoff time.Duration
+
+	// GrowAfter is the number of successful batches after which a shrunk batch size is doubled again
+	GrowAfter int
+}
+
+// DefaultRetryPolicy is used when the context carries no policy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	GrowAfter:      2,
+}
+
+// backoff returns the jittered wait before retry number attempt, counted from 0
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := policy.InitialBackoff
+	for i := 0; i < attempt && backoff < policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	// wait between half and the full backoff so failing requests do not retry in lockstep
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// retryPolicyKey carries the RetryPolicy in the context
+type retryPolicyKey struct{}
+
+// WithRetryPolicy makes the batches fetched with ctx follow policy
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+func retryPolicyFrom(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// errorAction is what a failed batch does next
+type errorAction int
+
+const (
+	// actionShrink fetches the batch again with half the size
+	actionShrink errorAction = iota
+
+	// actionRetry fetches the same batch again after a backoff
+	actionRetry
+
+	// actionFail gives up at once
+	actionFail
+)
+
+// classifyError maps the error of an access node call to the next action.
+// Errors without a gRPC status keep the former behavior of shrinking the batch.
+func classifyError(err error) errorAction {
+	if errors.Is(err, errBatchTimeout) {
+		return actionShrink
+	}
+	// access nodes reject wide ranges and large responses with different codes, the message tells
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "larger than max") || strings.Contains(message, "too large") || strings.Contains(message, "exceeded maximum") {
+		return actionShrink
+	}
+
+	switch status.Code(err) {
+	case codes.ResourceExhausted:
+		return actionShrink
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return actionRetry
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Unauthenticated, codes.Unimplemented:
+		return actionFail
+	default:
+		return actionShrink
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,111 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testRetryPolicy retries like DefaultRetryPolicy without slowing the tests down
+var testRetryPolicy = RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, GrowAfter: 2}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err    error
+		action errorAction
+	}{
+		{err: status.Error(codes.ResourceExhausted, "grpc: received message larger than max (50000000 vs. 40000000)"), action: actionShrink},
+		{err: status.Error(codes.InvalidArgument, "requested block range (500) exceeded maximum (250)"), action: actionShrink},
+		{err: client.RPCError{GRPCErr: status.Error(codes.ResourceExhausted, "height range too large")}, action: actionShrink},
+		{err: fmt.Errorf("%w: %v", errBatchTimeout, status.Error(codes.DeadlineExceeded, "context deadline exceeded")), action: actionShrink},
+		{err: status.Error(codes.Unavailable, "connection refused"), action: actionRetry},
+		{err: client.RPCError{GRPCErr: status.Error(codes.DeadlineExceeded, "upstream timeout")}, action: actionRetry},
+		{err: status.Error(codes.InvalidArgument, "invalid event type"), action: actionFail},
+		{err: status.Error(codes.Unauthenticated, "invalid api key"), action: actionFail},
+		{err: status.Error(codes.Internal, "internal"), action: actionShrink},
+		{err: errors.New("unknown"), action: actionShrink},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.action, classifyError(c.err), c.err.Error())
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 20; i++ {
+			backoff := policy.backoff(attempt)
+			require.GreaterOrEqual(t, backoff, max/2, "attempt %d", attempt)
+			require.LessOrEqual(t, backoff, max, "attempt %d", attempt)
+		}
+	}
+	require.Equal(t, time.Duration(0), RetryPolicy{}.backoff(3))
+}
+
+func TestForEachEventByBlockRangeRetriesTransientErrors(t *testing.T) {
+	ctx := WithRetryPolicy(context.Background(), testRetryPolicy)
+
+	node := &fakeAccessNode{err: status.Error(codes.Unavailable, "node restarting"), failFor: 2}
+	events, err := IterQueryEventByBlockRange(ctx, newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 99, 100)
+	require.Nil(t, err)
+	require.Len(t, events, 100, "the batch is retried whole, not shrunk")
+	require.Equal(t, 3, node.callCount())
+
+	// the retry budget is spent
+	policy := testRetryPolicy
+	policy.MaxRetries = 1
+	node = &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}
+	_, err = IterQueryEventByBlockRange(WithRetryPolicy(context.Background(), policy), newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 99, 100)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, 2, node.callCount())
+
+	// a rejected request fails at once
+	node = &fakeAccessNode{err: status.Error(codes.InvalidArgument, "invalid event type")}
+	_, err = IterQueryEventByBlockRange(ctx, newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 99, 100)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, node.callCount())
+}
+
+func TestForEachEventByBlockRangeGrowsBatchBack(t *testing.T) {
+	ctx := WithRetryPolicy(context.Background(), testRetryPolicy)
+	node := &fakeAccessNode{err: status.Error(codes.ResourceExhausted, "grpc: received message larger than max"), failFor: 1}
+
+	chunks := make([]heightRange, 0)
+	err := ForEachEventByBlockRange(ctx, newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 499, 100, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{0, 49}, {50, 99}, {100, 199}, {200, 299}, {300, 399}, {400, 499}}, chunks)
+
+	// a node that keeps rejecting wide ranges is not asked for the default size after every batch
+	node = &fakeAccessNode{maxRange: 50}
+	_, err = IterQueryEventByBlockRange(WithRetryPolicy(context.Background(), RetryPolicy{GrowAfter: 4}), newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 999, 100)
+	require.Nil(t, err)
+	require.Equal(t, 25, node.callCount())
+}
+
+func TestSporkStoreRetryPolicy(t *testing.T) {
+	node := &fakeAccessNode{err: status.Error(codes.Unavailable, "node restarting"), failFor: 2}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, node))
+
+	ret, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 199)
+	require.Nil(t, err)
+	require.Len(t, ret, 200)
+	require.Equal(t, 4, node.callCount())
+
+	ss.SetRetryPolicy(RetryPolicy{})
+	node.Lock()
+	node.calls = 0
+	node.Unlock()
+	_, err = ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 199)
+	require.NotNil(t, err)
+	require.Equal(t, 1, node.callCount())
+}
@@ -19,27 +19,49 @@ package spork
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/client"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+	pbv2 "github.com/MatrixLabsTech/flow-event-fetcher/proto/v2"
 )
 
 type FlowClient interface {
 	String() string
-	QueryEventByBlockRange(event string, start uint64, end uint64) ([]client.BlockEvents, error)
-	QueryLatestBlockHeight() (uint64, error)
+	QueryEventByBlockRange(ctx context.Context, events []string, start uint64, end uint64) ([]client.BlockEvents, error)
+	StreamEventByBlockRange(ctx context.Context, events []string, start uint64, end uint64, handler BlockEventsHandler) error
+	QueryLatestBlockHeight(ctx context.Context) (uint64, error)
+	// QueryBlockHeightByTime returns the first sealed height whose block is not older than timestamp,
+	// the height after the sealed head when every block is older
+	QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error)
+	// QueryBlockHeaderByHeight reads a sealed block header from the spork holding height
+	QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error)
+	// QueryTransaction reads a transaction and its result from the spork holding height, the height of its block
+	QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error)
 	SyncSpork() error
 	Close() error
 }
 
+// BlockEventsHandler receives the events of the block range [start, end] as soon as the range is fetched.
+// Returning an error stops the iteration.
+type BlockEventsHandler func(start uint64, end uint64, blockEvents []client.BlockEvents) error
+
 type ResolvedAccessNodeList struct {
-	Start      uint64
-	End        uint64
-	AccessNode string
+	Start       uint64
+	End         uint64
+	AccessNode  string
+	AccessNodes []string
 }
 
 type EventResult struct {
@@ -86,21 +108,36 @@ func EventToJSON(e *cadence.Event) []*pb.QueryEventByBlockRangeResponseValue {
 		value := e.Fields[i]
 		preparedFields = append(preparedFields,
 			&pb.QueryEventByBlockRangeResponseValue{
-				Name:  field.Identifier,
-				Value: value.String(),
+				Name:       field.Identifier,
+				Value:      value.String(),
+				Type:       cadenceTypeID(field.Type),
+				TypedValue: CadenceValueToProto(value),
 			},
 		)
 	}
 	return preparedFields
 }
 
+// EventJSONOptions controls what BlockEventsToJSONWithOptions includes per event
+type EventJSONOptions struct {
+	// IncludePayload keeps the raw JSON-CDC payload returned by the access node
+	IncludePayload bool
+
+	// Enricher, when set, attaches the blocks and transactions it fetched
+	Enricher *Enricher
+}
+
 func BlockEventsToJSON(e []client.BlockEvents) []*pb.QueryEventByBlockRangeResponseEvent {
+	return BlockEventsToJSONWithOptions(e, EventJSONOptions{})
+}
+
+func BlockEventsToJSONWithOptions(e []client.BlockEvents, opts EventJSONOptions) []*pb.QueryEventByBlockRangeResponseEvent {
 	result := make([]*pb.QueryEventByBlockRangeResponseEvent, 0)
 
 	for _, blockEvent := range e {
 		if len(blockEvent.Events) > 0 {
 			for _, event := range blockEvent.Events {
-				result = append(result, &pb.QueryEventByBlockRangeResponseEvent{
+				jsonEvent := &pb.QueryEventByBlockRangeResponseEvent{
 					BlockId:          blockEvent.Height,
 					Index:            int64(event.EventIndex),
 					Type:             event.Type,
@@ -109,7 +146,15 @@ func BlockEventsToJSON(e []client.BlockEvents) []*pb.QueryEventByBlockRangeRespo
 					TransactionId:    event.TransactionID.String(),
 					TransactionIndex: int64(event.TransactionIndex),
 					Values:           EventToJSON(&(event.Value)),
-				})
+				}
+				if opts.IncludePayload {
+					jsonEvent.Payload = event.Payload
+					jsonEvent.PayloadJson = string(event.Payload)
+				}
+				if opts.Enricher != nil {
+					jsonEvent.Block, jsonEvent.Transaction = opts.Enricher.contexts(blockEvent.Height, event.TransactionID)
+				}
+				result = append(result, jsonEvent)
 			}
 		}
 	}
@@ -117,48 +162,371 @@ func BlockEventsToJSON(e []client.BlockEvents) []*pb.QueryEventByBlockRangeRespo
 	return result
 }
 
-func IterQueryEventByBlockRange(ctx context.Context, ss *client.Client, event string, start uint64, end uint64, defaultBatchSize uint64) ([]client.BlockEvents, error) {
+// BlockEventsToV2 is BlockEventsToJSONWithOptions with the real block ID and the full precision block timestamp
+func BlockEventsToV2(e []client.BlockEvents, opts EventJSONOptions) []*pbv2.Event {
+	result := make([]*pbv2.Event, 0)
+
+	for _, blockEvent := range e {
+		for _, event := range blockEvent.Events {
+			v2Event := &pbv2.Event{
+				BlockHeight:      blockEvent.Height,
+				BlockId:          blockEvent.BlockID.String(),
+				Timestamp:        timestamppb.New(blockEvent.BlockTimestamp),
+				EventId:          event.ID(),
+				Index:            int64(event.EventIndex),
+				Type:             event.Type,
+				TransactionId:    event.TransactionID.String(),
+				TransactionIndex: int64(event.TransactionIndex),
+				Values:           EventToJSON(&(event.Value)),
+			}
+			if opts.IncludePayload {
+				v2Event.Payload = event.Payload
+				v2Event.PayloadJson = string(event.Payload)
+			}
+			if opts.Enricher != nil {
+				v2Event.Block, v2Event.Transaction = opts.Enricher.contexts(blockEvent.Height, event.TransactionID)
+			}
+			result = append(result, v2Event)
+		}
+	}
+
+	return result
+}
+
+func IterQueryEventByBlockRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64, defaultBatchSize uint64) ([]client.BlockEvents, error) {
 	events := make([]client.BlockEvents, 0)
-	tmpQueryBatchSize := defaultBatchSize
-	for i := start; i <= end; i += tmpQueryBatchSize {
-		// reset tmpQueryBatchSize to default
-		tmpQueryBatchSize = defaultBatchSize
+	err := ForEachEventByBlockRange(ctx, ss, eventTypes, start, end, defaultBatchSize, func(_ uint64, _ uint64, results []client.BlockEvents) error {
+		events = append(events, results...)
+		return nil
+	})
+	if err != nil && !IsPartialError(err) {
+		return nil, err
+	}
+
+	return events, err
+}
+
+// ParallelIterQueryEventByBlockRange is IterQueryEventByBlockRange fetching up to concurrency batches at once
+func ParallelIterQueryEventByBlockRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64, defaultBatchSize uint64, concurrency int) ([]client.BlockEvents, error) {
+	events := make([]client.BlockEvents, 0)
+	err := ParallelForEachEventByBlockRange(ctx, ss, eventTypes, start, end, defaultBatchSize, concurrency, func(_ uint64, _ uint64, results []client.BlockEvents) error {
+		events = append(events, results...)
+		return nil
+	})
+	if err != nil && !IsPartialError(err) {
+		return nil, err
+	}
+	return events, err
+}
+
+// batchResult is a batch fetched by a ParallelForEachEventByBlockRange worker
+type batchResult struct {
+	start uint64
+
+	end uint64
+
+	events []client.BlockEvents
+
+	err error
+
+	done chan struct{}
+}
 
-		// get the block range
-		startBlock := i
+// ParallelForEachEventByBlockRange fetches up to concurrency batches at once.
+// Every batch keeps the halving on error, batches are passed to handler in height order.
+func ParallelForEachEventByBlockRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64, defaultBatchSize uint64, concurrency int, handler BlockEventsHandler) error {
+	if concurrency <= 1 {
+		return ForEachEventByBlockRange(ctx, ss, eventTypes, start, end, defaultBatchSize, handler)
+	}
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
 
-		for {
-			endBlock := i + tmpQueryBatchSize - 1
-			if endBlock > end {
-				endBlock = end
+	return forEachBatch(ctx, start, end, defaultBatchSize, concurrency, func(ctx context.Context, start uint64, end uint64) ([]client.BlockEvents, error) {
+		return IterQueryEventByBlockRange(ctx, ss, eventTypes, start, end, defaultBatchSize)
+	}, handler)
+}
+
+// batchFetcher fetches all the events of one batch
+type batchFetcher func(ctx context.Context, start uint64, end uint64) ([]client.BlockEvents, error)
+
+// forEachBatch splits start - end into batches, fetches up to concurrency of them at once
+// and passes them to handler in height order. In partial mode failed batches are reported instead.
+func forEachBatch(ctx context.Context, start uint64, end uint64, batchSize uint64, concurrency int, fetch batchFetcher, handler BlockEventsHandler) error {
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the consumer waits on one batch while concurrency-1 are queued, so at most concurrency are in flight
+	pending := make(chan *batchResult, concurrency-1)
+	var stopped error
+	go func() {
+		defer close(pending)
+		for i := start; i <= end; i += batchSize {
+			batch := &batchResult{start: i, end: i + batchSize - 1, done: make(chan struct{})}
+			if batch.end > end || batch.end < i {
+				batch.end = end
+			}
+			select {
+			case pending <- batch:
+			case <-ctx.Done():
+				stopped = ctx.Err()
+				return
 			}
+			go func() {
+				defer close(batch.done)
+				batch.events, batch.err = fetch(ctx, batch.start, batch.end)
+			}()
+			if batch.end == end {
+				return
+			}
+		}
+	}()
 
-			log.Info("query block range: ", startBlock, " - ", endBlock)
-			results, err := ss.GetEventsForHeightRange(ctx, client.EventRangeQuery{
-				Type:        event,
-				StartHeight: startBlock,
-				EndHeight:   endBlock,
-			})
+	partial := partialResults(ctx)
+	failedRanges := make([]FailedRange, 0)
+	var err error
+	for batch := range pending {
+		<-batch.done
+		if err != nil {
+			// drain the batches already started
+			continue
+		}
+		switch {
+		case batch.err == nil || IsPartialError(batch.err):
+			failedRanges = append(failedRanges, failedRangesOf(batch.err)...)
+			err = handler(batch.start, batch.end, batch.events)
+		case partial && ctx.Err() == nil && classifyError(batch.err) != actionFail:
+			log.Error("FlowClient: skip failed height range ", batch.start, " - ", batch.end, ": ", batch.err)
+			failedRanges = append(failedRanges, FailedRange{Start: batch.start, End: batch.end, Err: batch.err})
+		default:
+			err = batch.err
+		}
+		if err != nil {
+			cancel()
+		}
+	}
+	// the producer stopped early because the parent context is done, the range is not complete
+	if err == nil {
+		err = stopped
+	}
+	if err == nil && len(failedRanges) > 0 {
+		err = newPartialError(failedRanges)
+	}
+	return err
+}
+
+// ForEachEventByBlockRange fetches the events batch by batch and passes every batch to handler in height order.
+// Failed batches are retried, shrunk or fail the request depending on the error, see classifyError and RetryPolicy.
+// In partial mode a batch that still fails is skipped and reported in a PartialError once the range is done.
+func ForEachEventByBlockRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64, defaultBatchSize uint64, handler BlockEventsHandler) error {
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+
+	policy := retryPolicyFrom(ctx)
+	partial := partialResults(ctx)
+	failedRanges := make([]FailedRange, 0)
+	tmpQueryBatchSize := defaultBatchSize
+	retries := 0
+	successes := 0
+	for startBlock := start; startBlock <= end; {
+		endBlock := startBlock + tmpQueryBatchSize - 1
+		if endBlock > end || endBlock < startBlock {
+			endBlock = end
+		}
+
+		log.Info("query block range: ", startBlock, " - ", endBlock)
+		results, err := queryEventsForHeightRange(ctx, ss, eventTypes, startBlock, endBlock)
 
-			if err != nil {
-				// log error with start and end
-				log.Error("FlowClient: failed to get events for height range", "start", startBlock, "end", endBlock, "err", err)
+		if err != nil {
+			// the caller is gone or out of time, a smaller batch will not help
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// log error with start and end
+			log.Error("FlowClient: failed to get events for height range", "start", startBlock, "end", endBlock, "err", err)
 
-				// return error if tmpQueryBatchSize = 1
-				if tmpQueryBatchSize == 1 {
-					return nil, err
+			switch classifyError(err) {
+			case actionFail:
+				return err
+			case actionRetry:
+				if retries < policy.MaxRetries {
+					backoff := policy.backoff(retries)
+					retries++
+					log.Info("FlowClient: retry ", retries, " of ", policy.MaxRetries, " in ", backoff)
+					if err := sleepContext(ctx, backoff); err != nil {
+						return err
+					}
+					continue
 				}
+			default:
+				if tmpQueryBatchSize > 1 {
+					// decrease tmpQueryBatchSize by half
+					tmpQueryBatchSize = tmpQueryBatchSize / 2
+					successes = 0
+					log.Info("FlowClient: decrease query batch size to ", tmpQueryBatchSize)
+					continue
+				}
+			}
 
-				// decrease tmpQueryBatchSize by half
-				tmpQueryBatchSize = tmpQueryBatchSize / 2
-				log.Info("SporkAlchemy: decrease query batch size to ", tmpQueryBatchSize)
+			// the batch cannot be fetched
+			if !partial {
+				return err
+			}
+			log.Error("FlowClient: skip failed height range ", startBlock, " - ", endBlock)
+			failedRanges = append(failedRanges, FailedRange{Start: startBlock, End: endBlock, Err: err})
+		} else {
+			if err := handler(startBlock, endBlock, results); err != nil {
+				return err
+			}
 
-				continue
+			// grow a shrunk batch size back towards the default once the node keeps up again
+			successes++
+			if tmpQueryBatchSize < defaultBatchSize && successes >= policy.GrowAfter {
+				tmpQueryBatchSize *= 2
+				if tmpQueryBatchSize > defaultBatchSize {
+					tmpQueryBatchSize = defaultBatchSize
+				}
+				successes = 0
+				log.Info("FlowClient: increase query batch size to ", tmpQueryBatchSize)
 			}
-			events = append(events, results...)
+		}
+
+		if endBlock == end {
 			break
 		}
+		startBlock = endBlock + 1
+		retries = 0
 	}
 
-	return events, nil
+	if len(failedRanges) > 0 {
+		return newPartialError(failedRanges)
+	}
+	return nil
+}
+
+// Timeouts bound a whole FlowClient request and every access node call in it, zero means no limit
+type Timeouts struct {
+	Request time.Duration
+
+	Batch time.Duration
+}
+
+// apply derives the context of a request
+func (timeouts Timeouts) apply(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = WithBatchTimeout(ctx, timeouts.Batch)
+	if timeouts.Request > 0 {
+		return context.WithTimeout(ctx, timeouts.Request)
+	}
+	return context.WithCancel(ctx)
+}
+
+// batchTimeoutKey carries the timeout of a single access node call in the context
+type batchTimeoutKey struct{}
+
+// WithBatchTimeout limits every access node call made with ctx to timeout, on top of the deadline of ctx
+func WithBatchTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, batchTimeoutKey{}, timeout)
+}
+
+// batchContext applies the batch timeout of ctx, if any
+func batchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, ok := ctx.Value(batchTimeoutKey{}).(time.Duration); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// queryEventsForHeightRange fetches every event type of the range concurrently within the batch timeout and merges the results
+func queryEventsForHeightRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	batchCtx, cancel := batchContext(ctx)
+	defer cancel()
+
+	results, err := queryEventTypesForHeightRange(batchCtx, ss, eventTypes, start, end)
+	// only the batch ran out of time, not the request: the batch is too large for the node
+	if err != nil && batchCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: %v", errBatchTimeout, err)
+	}
+	return results, err
+}
+
+// queryEventTypesForHeightRange runs one access node call per event type
+func queryEventTypesForHeightRange(ctx context.Context, ss *client.Client, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	if len(eventTypes) == 1 {
+		return ss.GetEventsForHeightRange(ctx, client.EventRangeQuery{
+			Type:        eventTypes[0],
+			StartHeight: start,
+			EndHeight:   end,
+		})
+	}
+
+	results := make([][]client.BlockEvents, len(eventTypes))
+	errs := make([]error, len(eventTypes))
+	var wg sync.WaitGroup
+	for i, eventType := range eventTypes {
+		wg.Add(1)
+		go func(i int, eventType string) {
+			defer wg.Done()
+			results[i], errs[i] = ss.GetEventsForHeightRange(ctx, client.EventRangeQuery{
+				Type:        eventType,
+				StartHeight: start,
+				EndHeight:   end,
+			})
+		}(i, eventType)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return MergeBlockEvents(results...), nil
+}
+
+// MergeBlockEvents merges block events of the same heights into one entry per block,
+// ordered by height, with the events of a block ordered by transaction and event index.
+func MergeBlockEvents(lists ...[]client.BlockEvents) []client.BlockEvents {
+	byHeight := make(map[uint64]*client.BlockEvents)
+	for _, list := range lists {
+		for _, blockEvent := range list {
+			merged, ok := byHeight[blockEvent.Height]
+			if !ok {
+				merged = &client.BlockEvents{
+					BlockID:        blockEvent.BlockID,
+					Height:         blockEvent.Height,
+					BlockTimestamp: blockEvent.BlockTimestamp,
+					Events:         make([]flow.Event, 0, len(blockEvent.Events)),
+				}
+				byHeight[blockEvent.Height] = merged
+			}
+			merged.Events = append(merged.Events, blockEvent.Events...)
+		}
+	}
+
+	result := make([]client.BlockEvents, 0, len(byHeight))
+	for _, merged := range byHeight {
+		sort.SliceStable(merged.Events, func(i, j int) bool {
+			if merged.Events[i].TransactionIndex != merged.Events[j].TransactionIndex {
+				return merged.Events[i].TransactionIndex < merged.Events[j].TransactionIndex
+			}
+			return merged.Events[i].EventIndex < merged.Events[j].EventIndex
+		})
+		result = append(result, *merged)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Height < result[j].Height
+	})
+	return result
 }
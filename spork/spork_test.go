@@ -0,0 +1,195 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMergeBlockEvents(t *testing.T) {
+	deposited := []client.BlockEvents{
+		{Height: 10, Events: []flow.Event{{Type: "Deposited", TransactionIndex: 1, EventIndex: 2}}},
+		{Height: 11, Events: []flow.Event{}},
+		{Height: 12, Events: []flow.Event{{Type: "Deposited", TransactionIndex: 0, EventIndex: 1}}},
+	}
+	withdrawn := []client.BlockEvents{
+		{Height: 10, Events: []flow.Event{{Type: "Withdrawn", TransactionIndex: 1, EventIndex: 1}, {Type: "Withdrawn", TransactionIndex: 0, EventIndex: 3}}},
+		{Height: 11, Events: []flow.Event{}},
+		{Height: 12, Events: []flow.Event{{Type: "Withdrawn", TransactionIndex: 0, EventIndex: 0}}},
+	}
+
+	merged := MergeBlockEvents(withdrawn, deposited)
+	require.Len(t, merged, 3)
+
+	require.Equal(t, uint64(10), merged[0].Height)
+	require.Equal(t, []flow.Event{
+		{Type: "Withdrawn", TransactionIndex: 0, EventIndex: 3},
+		{Type: "Withdrawn", TransactionIndex: 1, EventIndex: 1},
+		{Type: "Deposited", TransactionIndex: 1, EventIndex: 2},
+	}, merged[0].Events)
+
+	require.Equal(t, uint64(11), merged[1].Height)
+	require.Len(t, merged[1].Events, 0)
+
+	require.Equal(t, uint64(12), merged[2].Height)
+	require.Equal(t, "Withdrawn", merged[2].Events[0].Type)
+	require.Equal(t, "Deposited", merged[2].Events[1].Type)
+}
+
+func TestParallelForEachEventByBlockRange(t *testing.T) {
+	node := &fakeAccessNode{latency: time.Millisecond}
+	flowClient := newFakeAccessNodeClient(t, node)
+
+	chunks := make([]heightRange, 0)
+	var lastHeight uint64
+	total := 0
+	err := ParallelForEachEventByBlockRange(context.Background(), flowClient, []string{cacheTestEvent}, 100, 1049, 100, 4, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		for _, blockEvent := range blockEvents {
+			require.Greater(t, blockEvent.Height, lastHeight)
+			lastHeight = blockEvent.Height
+		}
+		total += len(blockEvents)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, chunks, 10)
+	require.Equal(t, heightRange{100, 199}, chunks[0])
+	require.Equal(t, heightRange{1000, 1049}, chunks[9])
+	require.Equal(t, 950, total)
+	require.LessOrEqual(t, node.maxInFlight, 4)
+	require.Greater(t, node.maxInFlight, 1)
+}
+
+func TestParallelForEachEventByBlockRangeHalving(t *testing.T) {
+	node := &fakeAccessNode{maxRange: 30}
+	flowClient := newFakeAccessNodeClient(t, node)
+
+	events, err := ParallelIterQueryEventByBlockRange(context.Background(), flowClient, []string{cacheTestEvent}, 0, 399, 100, 4)
+	require.Nil(t, err)
+	require.Len(t, events, 400)
+	for i, blockEvent := range events {
+		require.Equal(t, uint64(i), blockEvent.Height)
+	}
+}
+
+func TestParallelForEachEventByBlockRangeError(t *testing.T) {
+	flowClient := newFakeAccessNodeClient(t, &fakeAccessNode{})
+
+	handlerErr := errors.New("handler failed")
+	calls := 0
+	err := ParallelForEachEventByBlockRange(context.Background(), flowClient, []string{cacheTestEvent}, 0, 999, 100, 4, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		calls++
+		if start == 200 {
+			return handlerErr
+		}
+		return nil
+	})
+	require.Equal(t, handlerErr, err)
+	require.Equal(t, 3, calls)
+
+	node := &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}
+	_, err = ParallelIterQueryEventByBlockRange(WithRetryPolicy(context.Background(), testRetryPolicy), newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 99, 8, 4)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "node down")
+}
+
+func TestForEachEventByBlockRangeStopsOnCancel(t *testing.T) {
+	node := &fakeAccessNode{latency: 10 * time.Millisecond}
+	flowClient := newFakeAccessNodeClient(t, node)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := ForEachEventByBlockRange(ctx, flowClient, []string{cacheTestEvent}, 0, 999, 100, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		cancel()
+		return nil
+	})
+	require.Equal(t, context.Canceled, err)
+	require.LessOrEqual(t, node.callCount(), 2)
+
+	// an expired request fails at once instead of halving the batch
+	node = &fakeAccessNode{latency: time.Second}
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	_, err = IterQueryEventByBlockRange(ctx, newFakeAccessNodeClient(t, node), []string{cacheTestEvent}, 0, 999, 100)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Less(t, time.Since(begin), time.Second)
+	require.Equal(t, 1, node.callCount())
+}
+
+func TestForEachEventByBlockRangeBatchTimeout(t *testing.T) {
+	node := &fakeAccessNode{blockLatency: time.Millisecond}
+	flowClient := newFakeAccessNodeClient(t, node)
+
+	// a batch of 200 blocks outlives the batch timeout and is fetched again in halves
+	ctx := WithBatchTimeout(context.Background(), 150*time.Millisecond)
+	chunks := make([]heightRange, 0)
+	err := ForEachEventByBlockRange(ctx, flowClient, []string{cacheTestEvent}, 0, 199, 200, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{0, 99}, {100, 199}}, chunks)
+	require.Equal(t, 3, node.callCount())
+}
+
+func benchmarkForEachEventByBlockRange(b *testing.B, concurrency int) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	flowClient := newFakeAccessNodeClient(b, &fakeAccessNode{latency: 5 * time.Millisecond})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := ParallelIterQueryEventByBlockRange(context.Background(), flowClient, []string{cacheTestEvent}, 0, 1999, 200, concurrency)
+		require.Nil(b, err)
+	}
+}
+
+func BenchmarkForEachEventByBlockRangeSequential(b *testing.B) {
+	benchmarkForEachEventByBlockRange(b, 1)
+}
+
+func BenchmarkForEachEventByBlockRangeParallel4(b *testing.B) {
+	benchmarkForEachEventByBlockRange(b, 4)
+}
+
+func BenchmarkForEachEventByBlockRangeParallel10(b *testing.B) {
+	benchmarkForEachEventByBlockRange(b, 10)
+}
+
+func TestBlockEventsToV2(t *testing.T) {
+	blockID := flow.HexToID("0a0b")
+	timestamp := time.Unix(1640000000, 123456789)
+	event := newTestDepositEvent(0)
+	event.TransactionID = flow.HexToID("02")
+	event.TransactionIndex = 1
+	event.EventIndex = 3
+	blockEvents := []client.BlockEvents{{BlockID: blockID, Height: 19050753, BlockTimestamp: timestamp, Events: []flow.Event{event}}}
+
+	events := BlockEventsToV2(blockEvents, EventJSONOptions{})
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(19050753), events[0].BlockHeight)
+	require.Equal(t, blockID.String(), events[0].BlockId)
+	require.Equal(t, int64(1640000000), events[0].Timestamp.Seconds)
+	require.Equal(t, int32(123456789), events[0].Timestamp.Nanos)
+	require.Equal(t, flow.HexToID("02").String(), events[0].TransactionId)
+	require.Equal(t, int64(1), events[0].TransactionIndex)
+	require.Equal(t, int64(3), events[0].Index)
+	require.Empty(t, events[0].Payload)
+
+	// v1 keeps the height in blockId and truncates the timestamp
+	v1Events := BlockEventsToJSON(blockEvents)
+	require.Equal(t, uint64(19050753), v1Events[0].BlockId)
+	require.Zero(t, v1Events[0].Timestamp.Nanos)
+}
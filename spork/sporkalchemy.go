@@ -4,100 +4,126 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/client"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
+// SporkAlchemy queries a single endpoint, all requests share one pooled connection.
+// The embedded mutex only guards the settings, reconnecting is left to the pool.
 type SporkAlchemy struct {
 	sync.Mutex
 
 	endPoint string
 
-	apiContext context.Context
+	header metadata.MD
 
-	flowClient *client.Client
+	pool *ClientPool
 
 	maxQueryBlocks uint64
 
 	queryBatchSize uint64
+
+	queryConcurrency int
+
+	timeouts Timeouts
+
+	retryPolicy RetryPolicy
+
+	// blockTimes caches the block timestamps read by QueryBlockHeightByTime
+	blockTimes *blockTimeIndex
 }
 
-// init initializes the spork alchemy
+// init dials the endpoint
 func (alchemy *SporkAlchemy) init() error {
-	alchemy.Lock()
-	defer alchemy.Unlock()
+	log.Info("SporkAlchemy: initializing flow client")
+	log.Info(alchemy.endPoint)
 
-	if alchemy.flowClient == nil {
-		err := alchemy.newClient()
-		if err != nil {
-			return err
-		}
+	flowClient, err := alchemy.pool.Get(alchemy.withAPIKey(context.Background()), alchemy.endPoint)
+	if err != nil {
+		log.Error("SporkAlchemy: failed to initialize flow client", "err", err)
+		return err
 	}
-
+	alchemy.pool.Release(alchemy.endPoint, flowClient)
 	return nil
 }
 
 func NewSporkAlchemy(endPoint string, apiKey string, maxQueryBlocks uint64, queryBatchSize uint64) *SporkAlchemy {
 	ss := &SporkAlchemy{
 		endPoint:       endPoint,
+		pool:           NewClientPool(clientIdleTimeout, clientHealthCheckInterval, grpc.WithInsecure(), grpc.WithMaxMsgSize(40e6)),
 		maxQueryBlocks: maxQueryBlocks,
 		queryBatchSize: queryBatchSize,
+
+		queryConcurrency: 1,
+		retryPolicy:      DefaultRetryPolicy,
+		blockTimes:       newBlockTimeIndex(maxBlockTimeSamples),
 	}
 
-	header := metadata.New(map[string]string{
+	ss.header = metadata.New(map[string]string{
 		"api_key": apiKey,
 	})
 
-	ss.apiContext = metadata.NewOutgoingContext(context.Background(), header)
-
 	// init the spork alchemy
 	ss.init()
 
 	return ss
 }
 
-func (alchemy *SporkAlchemy) checkClientHealthy() error {
-	err := alchemy.flowClient.Ping(alchemy.apiContext)
-	if err != nil {
-		// close previous client
-		alchemy.flowClient.Close()
-
-		// create new client
-		log.Error("SporkAlchemy: flow client is not healthy with error ", err)
-		log.Info("SporkAlchemy: trying to reinitialize flow client")
-		return alchemy.newClient()
-	}
-	return nil
+// withAPIKey attaches the api key to the outgoing calls made with ctx
+func (alchemy *SporkAlchemy) withAPIKey(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, alchemy.header)
 }
 
-func (alchemy *SporkAlchemy) newClient() error {
-	log.Info("SporkAlchemy: initializing flow client")
-	log.Info(alchemy.endPoint)
+// acquireClient returns the shared flow client, pinged first when it was not checked for a while.
+// Every acquireClient must be paired with a releaseClient.
+func (alchemy *SporkAlchemy) acquireClient(ctx context.Context) (*client.Client, error) {
+	return alchemy.pool.Get(alchemy.withAPIKey(ctx), alchemy.endPoint)
+}
 
-	flowClient, err := client.New(alchemy.endPoint, grpc.WithInsecure(), grpc.WithMaxMsgSize(40e6))
-	if err != nil {
-		log.Error("SporkAlchemy: failed to initialize flow client", "err", err)
-		return err
+// releaseClient hands the flow client back, a client failing with err is pinged and dropped when unhealthy
+func (alchemy *SporkAlchemy) releaseClient(ctx context.Context, flowClient *client.Client, err error) {
+	defer alchemy.pool.Release(alchemy.endPoint, flowClient)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if pingErr := flowClient.Ping(alchemy.withAPIKey(ctx)); pingErr != nil {
+		log.Error("SporkAlchemy: flow client is not healthy with error ", pingErr)
+		log.Info("SporkAlchemy: the next request reinitializes the flow client")
+		alchemy.pool.Discard(alchemy.endPoint, flowClient)
 	}
-	alchemy.flowClient = flowClient
-	return nil
 }
 
-// QueryLatestBlockHeight
-func (alchemy *SporkAlchemy) QueryLatestBlockHeight() (uint64, error) {
-	// thread safe
+// settings returns the query settings, they may be changed while requests are running
+func (alchemy *SporkAlchemy) settings() (uint64, int) {
+	alchemy.Lock()
+	defer alchemy.Unlock()
+	return alchemy.queryBatchSize, alchemy.queryConcurrency
+}
+
+// requestContext applies the timeouts and the retry policy to the context of a request
+func (alchemy *SporkAlchemy) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	alchemy.Lock()
 	defer alchemy.Unlock()
+	return alchemy.timeouts.apply(WithRetryPolicy(ctx, alchemy.retryPolicy))
+}
+
+// QueryLatestBlockHeight
+func (alchemy *SporkAlchemy) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
+	ctx, cancel := alchemy.requestContext(ctx)
+	defer cancel()
 
-	err := alchemy.checkClientHealthy()
+	flowClient, err := alchemy.acquireClient(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	block, err := alchemy.flowClient.GetLatestBlock(context.Background(), true)
+	block, err := flowClient.GetLatestBlock(alchemy.withAPIKey(ctx), true)
+	alchemy.releaseClient(ctx, flowClient, err)
 	if err != nil {
 		return 0, err
 	}
@@ -105,29 +131,112 @@ func (alchemy *SporkAlchemy) QueryLatestBlockHeight() (uint64, error) {
 	return block.Height, nil
 }
 
-// queryEventByBlockRange
-func (alchemy *SporkAlchemy) QueryEventByBlockRange(event string, start uint64, end uint64) ([]client.BlockEvents, error) {
-	// thread safe
-	alchemy.Lock()
-	defer alchemy.Unlock()
+// QueryBlockHeightByTime searches the block headers up to the sealed head, alchemy serves every spork
+func (alchemy *SporkAlchemy) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	ctx, cancel := alchemy.requestContext(ctx)
+	defer cancel()
 
-	err := alchemy.checkClientHealthy()
+	latestHeight, err := alchemy.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return alchemy.blockTimes.search(ctx, timestamp, 0, latestHeight, func(ctx context.Context, height uint64) (time.Time, error) {
+		header, err := alchemy.queryBlockHeader(ctx, height)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return header.Timestamp, nil
+	})
+}
+
+// QueryBlockHeaderByHeight reads a block header of any spork
+func (alchemy *SporkAlchemy) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	ctx, cancel := alchemy.requestContext(ctx)
+	defer cancel()
+	return alchemy.queryBlockHeader(ctx, height)
+}
+
+// QueryTransaction reads a transaction of any spork
+func (alchemy *SporkAlchemy) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error) {
+	ctx, cancel := alchemy.requestContext(ctx)
+	defer cancel()
+
+	flowClient, err := alchemy.acquireClient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	tx, err := flowClient.GetTransaction(alchemy.withAPIKey(ctx), txID)
+	var result *flow.TransactionResult
+	if err == nil {
+		result, err = flowClient.GetTransactionResult(alchemy.withAPIKey(ctx), txID)
+	}
+	alchemy.releaseClient(ctx, flowClient, err)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionInfo(tx, result), nil
+}
+
+func (alchemy *SporkAlchemy) queryBlockHeader(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	flowClient, err := alchemy.acquireClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := flowClient.GetBlockHeaderByHeight(alchemy.withAPIKey(ctx), height)
+	alchemy.releaseClient(ctx, flowClient, err)
+	return header, err
+}
 
+// queryEventByBlockRange
+func (alchemy *SporkAlchemy) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
 	events := make([]client.BlockEvents, 0)
+	err := alchemy.StreamEventByBlockRange(ctx, eventTypes, start, end, func(_ uint64, _ uint64, ret []client.BlockEvents) error {
+		events = append(events, ret...)
+		return nil
+	})
+	if err != nil && !IsPartialError(err) {
+		return nil, err
+	}
+
+	return events, err
+}
+
+// StreamEventByBlockRange passes each fetched batch to handler, concurrent streams share the connection
+func (alchemy *SporkAlchemy) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler BlockEventsHandler) error {
+	tmpQueryBatchSize, queryConcurrency := alchemy.settings()
+	ctx, cancel := alchemy.requestContext(ctx)
+	defer cancel()
+
+	flowClient, err := alchemy.acquireClient(ctx)
+	if err != nil {
+		return err
+	}
 
-	tmpQueryBatchSize := alchemy.queryBatchSize
+	err = ParallelForEachEventByBlockRange(alchemy.withAPIKey(ctx), flowClient, eventTypes, start, end, tmpQueryBatchSize, queryConcurrency, handler)
+	alchemy.releaseClient(ctx, flowClient, err)
+	return err
+}
 
+// SetQueryConcurrency sets how many batches are fetched at once, 1 fetches them one after another
+func (alchemy *SporkAlchemy) SetQueryConcurrency(concurrency int) {
+	alchemy.Lock()
+	defer alchemy.Unlock()
+	alchemy.queryConcurrency = concurrency
+}
 
-    ret, err := IterQueryEventByBlockRange(alchemy.apiContext, alchemy.flowClient, event, start, end, tmpQueryBatchSize)
-    if err != nil {
-        return nil, err
-    }
-    events = append(events, ret...)
+// SetTimeouts bounds every request and every access node call in it
+func (alchemy *SporkAlchemy) SetTimeouts(timeouts Timeouts) {
+	alchemy.Lock()
+	defer alchemy.Unlock()
+	alchemy.timeouts = timeouts
+}
 
-	return events, nil
+// SetRetryPolicy sets how failed batches are retried
+func (alchemy *SporkAlchemy) SetRetryPolicy(policy RetryPolicy) {
+	alchemy.Lock()
+	defer alchemy.Unlock()
+	alchemy.retryPolicy = policy
 }
 
 // SyncSpork with not implementation log
@@ -143,10 +252,7 @@ func (alchemy *SporkAlchemy) String() string {
 
 // close the spork alchemy
 func (alchemy *SporkAlchemy) Close() error {
-    if alchemy.flowClient != nil {
-        err := alchemy.flowClient.Close()
-        log.Info("SporkAlchemy: flow client closed")
-        return err
-    }
-    return nil
+	err := alchemy.pool.Close()
+	log.Info("SporkAlchemy: flow client closed")
+	return err
 }
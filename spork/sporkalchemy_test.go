@@ -0,0 +1,94 @@
+package spork
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSporkAlchemy(t *testing.T, node *fakeAccessNode) *SporkAlchemy {
+	alchemy := NewSporkAlchemy(newFakeAccessNodeAddr(t, node), node.apiKey, 2000, 100)
+	t.Cleanup(func() { alchemy.Close() })
+	return alchemy
+}
+
+func TestSporkAlchemyConcurrentQueries(t *testing.T) {
+	node := &fakeAccessNode{head: 5000, latency: 20 * time.Millisecond, apiKey: "secret"}
+	alchemy := newTestSporkAlchemy(t, node)
+
+	// every query takes ten sequential batches, they must not wait for each other
+	const queries = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, queries)
+	for i := 0; i < queries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ret, err := alchemy.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 999)
+			if events := BlockEventsToJSON(ret); err == nil && len(events) != 200 {
+				err = fmt.Errorf("got %d events", len(events))
+			}
+			errs <- err
+		}()
+	}
+
+	// the head is served while the large queries are running
+	time.Sleep(10 * time.Millisecond)
+	headBegin := time.Now()
+	height, err := alchemy.QueryLatestBlockHeight(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, uint64(5000), height)
+	// behind a global lock it would wait for all the queries, 3.2s of node latency
+	require.Less(t, time.Since(headBegin), 500*time.Millisecond)
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+	require.Equal(t, queries*10, node.callCount())
+	node.Lock()
+	require.Greater(t, node.maxInFlight, queries/2, "the queries run side by side")
+	node.Unlock()
+	require.Equal(t, 1, alchemy.pool.Len(), "the queries share one connection")
+}
+
+func TestSporkAlchemySettingsDuringQueries(t *testing.T) {
+	node := &fakeAccessNode{latency: time.Millisecond}
+	alchemy := newTestSporkAlchemy(t, node)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			alchemy.SetQueryConcurrency(i%3 + 1)
+			alchemy.SetTimeouts(Timeouts{Request: time.Minute})
+			_, err := alchemy.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 499)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+}
+
+func TestSporkAlchemyQueryBlockHeightByTime(t *testing.T) {
+	node := &fakeAccessNode{head: 5000, apiKey: "secret"}
+	alchemy := newTestSporkAlchemy(t, node)
+
+	height, err := alchemy.QueryBlockHeightByTime(context.Background(), time.Unix(1234, 1))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1235), height)
+
+	height, err = alchemy.QueryBlockHeightByTime(context.Background(), time.Unix(9999, 0))
+	require.Nil(t, err)
+	require.Equal(t, uint64(5001), height)
+}
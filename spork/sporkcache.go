@@ -0,0 +1,486 @@
+/**
+ * spork/sporkcache.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	eventsBucket   = []byte("events")
+	coverageBucket = []byte("coverage")
+)
+
+// SporkCache is a FlowClient keeping the fetched events of sealed blocks in a local bbolt file.
+// For every event type of a network it records the height intervals already fetched, so only the missing
+// gaps of a query are fetched from the backend. Blocks without events are not stored.
+type SporkCache struct {
+	sync.Mutex
+
+	backend FlowClient
+
+	db *bolt.DB
+
+	path string
+
+	// network scopes the cached events, one cache file can be shared by several networks
+	network string
+
+	maxQueryBlocks uint64
+
+	streamBatchSize uint64
+
+	// sealedHeight is the last known sealed height, blocks above it are never cached
+	sealedHeight uint64
+}
+
+type heightRange struct {
+	Start uint64
+	End   uint64
+}
+
+type cachedEvent struct {
+	Type             string `json:"type"`
+	TransactionID    string `json:"transactionId"`
+	TransactionIndex int    `json:"transactionIndex"`
+	EventIndex       int    `json:"eventIndex"`
+	Payload          []byte `json:"payload"`
+}
+
+type cachedBlockEvents struct {
+	BlockID        string        `json:"blockId"`
+	Height         uint64        `json:"height"`
+	BlockTimestamp time.Time     `json:"blockTimestamp"`
+	Events         []cachedEvent `json:"events"`
+}
+
+// NewSporkCache opens (or creates) the cache file at path in front of backend, serving the events of network.
+// maxQueryBlocks bounds a query like it bounds the backend, cached or not.
+// streamBatchSize is the block range handed to the handler of StreamEventByBlockRange.
+func NewSporkCache(backend FlowClient, path string, network string, maxQueryBlocks uint64, streamBatchSize uint64) (*SporkCache, error) {
+	if network == "" {
+		return nil, errors.New("network is required")
+	}
+	if streamBatchSize == 0 {
+		return nil, errors.New("stream batch size must be greater than 0")
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(eventsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(coverageBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SporkCache{backend: backend, db: db, path: path, network: network, maxQueryBlocks: maxQueryBlocks, streamBatchSize: streamBatchSize}, nil
+}
+
+func (cache *SporkCache) String() string {
+	return fmt.Sprintf("SporkCache{path: %s, network: %s, maxQueryBlocks: %d, streamBatchSize: %d, backend: %s}", cache.path, cache.network, cache.maxQueryBlocks, cache.streamBatchSize, cache.backend.String())
+}
+
+func (cache *SporkCache) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
+	height, err := cache.backend.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cache.Lock()
+	if height > cache.sealedHeight {
+		cache.sealedHeight = height
+	}
+	cache.Unlock()
+	return height, nil
+}
+
+// QueryBlockHeightByTime is not cached here, the backend keeps the block timestamps it reads
+func (cache *SporkCache) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	return cache.backend.QueryBlockHeightByTime(ctx, timestamp)
+}
+
+func (cache *SporkCache) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	return cache.backend.QueryBlockHeaderByHeight(ctx, height)
+}
+
+func (cache *SporkCache) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error) {
+	return cache.backend.QueryTransaction(ctx, height, txID)
+}
+
+func (cache *SporkCache) SyncSpork() error {
+	return cache.backend.SyncSpork()
+}
+
+func (cache *SporkCache) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	if len(eventTypes) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	if start > end {
+		return nil, errors.New("start must not be greater than end")
+	}
+	// checked before the cache, a range is served the same whether it is cached or not
+	if end-start > cache.maxQueryBlocks {
+		return nil, ErrRangeTooLarge
+	}
+
+	sealedHeight, err := cache.sealedHeightFor(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]client.BlockEvents, 0, len(eventTypes))
+	failedRanges := make([]FailedRange, 0)
+	for _, eventType := range eventTypes {
+		ret, err := cache.queryEventType(ctx, eventType, start, end, sealedHeight)
+		if err != nil && !IsPartialError(err) {
+			return nil, err
+		}
+		failedRanges = append(failedRanges, failedRangesOf(err)...)
+		results = append(results, ret)
+	}
+	if len(failedRanges) > 0 {
+		return MergeBlockEvents(results...), newPartialError(failedRanges)
+	}
+	return MergeBlockEvents(results...), nil
+}
+
+func (cache *SporkCache) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler BlockEventsHandler) error {
+	if start <= end && end-start > cache.maxQueryBlocks {
+		return ErrRangeTooLarge
+	}
+	failedRanges := make([]FailedRange, 0)
+	for i := start; i <= end; i += cache.streamBatchSize {
+		batchEnd := i + cache.streamBatchSize - 1
+		if batchEnd > end || batchEnd < i {
+			batchEnd = end
+		}
+		ret, err := cache.QueryEventByBlockRange(ctx, eventTypes, i, batchEnd)
+		if err != nil && !IsPartialError(err) {
+			return err
+		}
+		// in partial mode the batch is handed over with its holes, they are reported once the range is done
+		failedRanges = append(failedRanges, failedRangesOf(err)...)
+		if err := handler(i, batchEnd, ret); err != nil {
+			return err
+		}
+		if batchEnd == end {
+			break
+		}
+	}
+	if len(failedRanges) > 0 {
+		return newPartialError(failedRanges)
+	}
+	return nil
+}
+
+// Close closes the cache file and the backend
+func (cache *SporkCache) Close() error {
+	err := cache.db.Close()
+	log.Info("SporkCache: cache closed")
+	if backendErr := cache.backend.Close(); backendErr != nil {
+		return backendErr
+	}
+	return err
+}
+
+// sealedHeightFor returns a sealed height, queried again only when end is above the known one
+func (cache *SporkCache) sealedHeightFor(ctx context.Context, end uint64) (uint64, error) {
+	cache.Lock()
+	sealedHeight := cache.sealedHeight
+	cache.Unlock()
+	if end <= sealedHeight {
+		return sealedHeight, nil
+	}
+	return cache.QueryLatestBlockHeight(ctx)
+}
+
+// queryEventType fetches the missing gaps of [start, end] and serves the range from the cache
+func (cache *SporkCache) queryEventType(ctx context.Context, eventType string, start uint64, end uint64, sealedHeight uint64) ([]client.BlockEvents, error) {
+	gaps, err := cache.missingRanges(eventType, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make([]client.BlockEvents, 0)
+	failedRanges := make([]FailedRange, 0)
+	for _, gap := range gaps {
+		log.Info("SporkCache: fetch ", eventType, " ", gap.Start, " - ", gap.End)
+		ret, err := cache.backend.QueryEventByBlockRange(ctx, []string{eventType}, gap.Start, gap.End)
+		if err != nil && !IsPartialError(err) {
+			return nil, err
+		}
+		// the holes of a partial gap are left uncached, the next query fetches them again
+		failed := failedRangesOf(err)
+		failedRanges = append(failedRanges, failed...)
+
+		// only sealed blocks can never change, the rest is served without being cached
+		stored := make([]heightRange, 0)
+		for _, covered := range subtractFailedRanges(gap, failed) {
+			if covered.Start > sealedHeight {
+				continue
+			}
+			if covered.End > sealedHeight {
+				covered.End = sealedHeight
+			}
+			if err := cache.store(eventType, covered, ret); err != nil {
+				return nil, err
+			}
+			stored = append(stored, covered)
+		}
+		for _, blockEvent := range ret {
+			if !inHeightRanges(blockEvent.Height, stored) && len(blockEvent.Events) > 0 {
+				fetched = append(fetched, blockEvent)
+			}
+		}
+	}
+
+	cached, err := cache.load(eventType, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(failedRanges) > 0 {
+		return MergeBlockEvents(cached, fetched), newPartialError(failedRanges)
+	}
+	return MergeBlockEvents(cached, fetched), nil
+}
+
+// subtractFailedRanges returns the parts of gap outside of the failed ranges
+func subtractFailedRanges(gap heightRange, failed []FailedRange) []heightRange {
+	sorted := append([]FailedRange(nil), failed...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+	result := make([]heightRange, 0, len(sorted)+1)
+	next := gap.Start
+	for _, failedRange := range sorted {
+		if failedRange.End < next || failedRange.Start > gap.End {
+			continue
+		}
+		if failedRange.Start > next {
+			result = append(result, heightRange{Start: next, End: failedRange.Start - 1})
+		}
+		if failedRange.End >= gap.End {
+			return result
+		}
+		next = failedRange.End + 1
+	}
+	return append(result, heightRange{Start: next, End: gap.End})
+}
+
+func inHeightRanges(height uint64, ranges []heightRange) bool {
+	for _, r := range ranges {
+		if height >= r.Start && height <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketKey names the buckets of eventType on the network of the cache
+func (cache *SporkCache) bucketKey(eventType string) []byte {
+	return []byte(cache.network + "/" + eventType)
+}
+
+func heightKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
+
+// coveredRanges returns the sorted, non overlapping intervals cached in the bucket named key
+func coveredRanges(tx *bolt.Tx, key []byte) []heightRange {
+	ranges := make([]heightRange, 0)
+	bucket := tx.Bucket(coverageBucket).Bucket(key)
+	if bucket == nil {
+		return ranges
+	}
+	bucket.ForEach(func(k, v []byte) error {
+		ranges = append(ranges, heightRange{Start: binary.BigEndian.Uint64(k), End: binary.BigEndian.Uint64(v)})
+		return nil
+	})
+	return ranges
+}
+
+// missingRanges returns the parts of [start, end] not yet cached for eventType
+func (cache *SporkCache) missingRanges(eventType string, start uint64, end uint64) ([]heightRange, error) {
+	gaps := make([]heightRange, 0)
+	err := cache.db.View(func(tx *bolt.Tx) error {
+		next := start
+		for _, covered := range coveredRanges(tx, cache.bucketKey(eventType)) {
+			if covered.End < next {
+				continue
+			}
+			if covered.Start > end {
+				break
+			}
+			if covered.Start > next {
+				gaps = append(gaps, heightRange{Start: next, End: covered.Start - 1})
+			}
+			next = covered.End + 1
+			if next > end {
+				return nil
+			}
+		}
+		gaps = append(gaps, heightRange{Start: next, End: end})
+		return nil
+	})
+	return gaps, err
+}
+
+// store saves the block events of covered and merges covered into the cached intervals
+func (cache *SporkCache) store(eventType string, covered heightRange, blockEvents []client.BlockEvents) error {
+	return cache.db.Update(func(tx *bolt.Tx) error {
+		events, err := tx.Bucket(eventsBucket).CreateBucketIfNotExists(cache.bucketKey(eventType))
+		if err != nil {
+			return err
+		}
+		for _, blockEvent := range blockEvents {
+			if len(blockEvent.Events) == 0 || blockEvent.Height < covered.Start || blockEvent.Height > covered.End {
+				continue
+			}
+			value, err := encodeBlockEvents(blockEvent)
+			if err != nil {
+				return err
+			}
+			if err := events.Put(heightKey(blockEvent.Height), value); err != nil {
+				return err
+			}
+		}
+
+		coverage, err := tx.Bucket(coverageBucket).CreateBucketIfNotExists(cache.bucketKey(eventType))
+		if err != nil {
+			return err
+		}
+		merged := covered
+		for _, r := range coveredRanges(tx, cache.bucketKey(eventType)) {
+			// merge overlapping and adjacent intervals
+			if r.End+1 < merged.Start || r.Start > merged.End+1 {
+				continue
+			}
+			if r.Start < merged.Start {
+				merged.Start = r.Start
+			}
+			if r.End > merged.End {
+				merged.End = r.End
+			}
+			if err := coverage.Delete(heightKey(r.Start)); err != nil {
+				return err
+			}
+		}
+		return coverage.Put(heightKey(merged.Start), heightKey(merged.End))
+	})
+}
+
+// load reads the cached block events of eventType in [start, end]
+func (cache *SporkCache) load(eventType string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	result := make([]client.BlockEvents, 0)
+	err := cache.db.View(func(tx *bolt.Tx) error {
+		events := tx.Bucket(eventsBucket).Bucket(cache.bucketKey(eventType))
+		if events == nil {
+			return nil
+		}
+		c := events.Cursor()
+		for k, v := c.Seek(heightKey(start)); k != nil && binary.BigEndian.Uint64(k) <= end; k, v = c.Next() {
+			blockEvent, err := decodeBlockEvents(v)
+			if err != nil {
+				return err
+			}
+			result = append(result, blockEvent)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func encodeBlockEvents(blockEvent client.BlockEvents) ([]byte, error) {
+	cached := cachedBlockEvents{
+		BlockID:        blockEvent.BlockID.Hex(),
+		Height:         blockEvent.Height,
+		BlockTimestamp: blockEvent.BlockTimestamp,
+		Events:         make([]cachedEvent, 0, len(blockEvent.Events)),
+	}
+	for _, event := range blockEvent.Events {
+		payload := event.Payload
+		if len(payload) == 0 {
+			var err error
+			payload, err = jsoncdc.Encode(event.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cached.Events = append(cached.Events, cachedEvent{
+			Type:             event.Type,
+			TransactionID:    event.TransactionID.Hex(),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Payload:          payload,
+		})
+	}
+	return json.Marshal(cached)
+}
+
+func decodeBlockEvents(value []byte) (client.BlockEvents, error) {
+	var cached cachedBlockEvents
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return client.BlockEvents{}, err
+	}
+	blockEvent := client.BlockEvents{
+		BlockID:        flow.HexToID(cached.BlockID),
+		Height:         cached.Height,
+		BlockTimestamp: cached.BlockTimestamp,
+		Events:         make([]flow.Event, 0, len(cached.Events)),
+	}
+	for _, event := range cached.Events {
+		value, err := jsoncdc.Decode(event.Payload)
+		if err != nil {
+			return client.BlockEvents{}, err
+		}
+		eventValue, ok := value.(cadence.Event)
+		if !ok {
+			return client.BlockEvents{}, fmt.Errorf("SporkCache: expected Event value, got %s", value.Type().ID())
+		}
+		blockEvent.Events = append(blockEvent.Events, flow.Event{
+			Type:             event.Type,
+			TransactionID:    flow.HexToID(event.TransactionID),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Value:            eventValue,
+			Payload:          event.Payload,
+		})
+	}
+	return blockEvent, nil
+}
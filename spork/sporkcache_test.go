@@ -0,0 +1,187 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+)
+
+const cacheTestEvent = "A.1654653399040a61.FlowToken.TokensDeposited"
+
+// countingFlowClient emits one deposit every fifth block and records the fetched ranges
+type countingFlowClient struct {
+	headFlowClient
+
+	fetched []heightRange
+
+	// failed ranges are left out and reported in a PartialError
+	failed []heightRange
+}
+
+func newTestDepositEvent(height uint64) flow.Event {
+	eventType := &cadence.EventType{
+		Location:            common.AddressLocation{Address: common.BytesToAddress([]byte{0x16, 0x54, 0x65, 0x33, 0x99, 0x04, 0x0a, 0x61}), Name: "FlowToken"},
+		QualifiedIdentifier: "FlowToken.TokensDeposited",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+		},
+	}
+	amount, _ := cadence.NewUFix64("1.5")
+	return flow.Event{
+		Type:          cacheTestEvent,
+		TransactionID: flow.HexToID("02"),
+		Value:         cadence.NewEvent([]cadence.Value{amount}).WithType(eventType),
+	}
+}
+
+func (f *countingFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	f.fetched = append(f.fetched, heightRange{Start: start, End: end})
+	result := make([]client.BlockEvents, 0)
+	failedRanges := make([]FailedRange, 0)
+	for _, failed := range f.failed {
+		if failed.Start <= end && failed.End >= start {
+			failedRanges = append(failedRanges, FailedRange{Start: failed.Start, End: failed.End, Err: errors.New("block not found")})
+		}
+	}
+	for height := start; height <= end; height++ {
+		if f.isFailed(height) {
+			continue
+		}
+		blockEvent := client.BlockEvents{
+			BlockID:        flow.HexToID("01"),
+			Height:         height,
+			BlockTimestamp: time.Unix(int64(height), 0).UTC(),
+			Events:         []flow.Event{},
+		}
+		if height%5 == 0 {
+			blockEvent.Events = append(blockEvent.Events, newTestDepositEvent(height))
+		}
+		result = append(result, blockEvent)
+	}
+	if len(failedRanges) > 0 {
+		return result, newPartialError(failedRanges)
+	}
+	return result, nil
+}
+
+func (f *countingFlowClient) isFailed(height uint64) bool {
+	for _, failed := range f.failed {
+		if height >= failed.Start && height <= failed.End {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestSporkCache(t *testing.T, backend FlowClient) *SporkCache {
+	cache, err := NewSporkCache(backend, filepath.Join(t.TempDir(), "cache.db"), "mainnet", 2000, 20)
+	require.Nil(t, err)
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
+func TestSporkCacheFetchesOnlyGaps(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}}
+	cache := newTestSporkCache(t, backend)
+
+	ret, err := cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	require.Nil(t, err)
+	require.Len(t, ret, 10)
+	require.Equal(t, []heightRange{{100, 149}}, backend.fetched)
+
+	ret, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	require.Nil(t, err)
+	require.Len(t, ret, 10)
+	require.Len(t, backend.fetched, 1, "a covered range should not be fetched again")
+
+	ret, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 90, 160)
+	require.Nil(t, err)
+	require.Len(t, ret, 15)
+	require.Equal(t, []heightRange{{100, 149}, {90, 99}, {150, 160}}, backend.fetched[:3])
+
+	for i, blockEvent := range ret {
+		require.Equal(t, uint64(90+5*i), blockEvent.Height)
+		require.Equal(t, time.Unix(int64(blockEvent.Height), 0).UTC(), blockEvent.BlockTimestamp.UTC())
+		require.Equal(t, cacheTestEvent, blockEvent.Events[0].Type)
+		require.Equal(t, "1.50000000", blockEvent.Events[0].Value.Fields[0].String())
+	}
+
+	gaps, err := cache.missingRanges(cacheTestEvent, 80, 170)
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{80, 89}, {161, 170}}, gaps)
+}
+
+func TestSporkCacheDoesNotCacheUnsealedBlocks(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 120}}
+	cache := newTestSporkCache(t, backend)
+
+	ret, err := cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 130)
+	require.Nil(t, err)
+	require.Len(t, ret, 7)
+
+	gaps, err := cache.missingRanges(cacheTestEvent, 100, 130)
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{121, 130}}, gaps)
+}
+
+func TestSporkCacheStream(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}}
+	cache := newTestSporkCache(t, backend)
+
+	chunks := make([]heightRange, 0)
+	total := 0
+	err := cache.StreamEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149, func(start uint64, end uint64, blockEvents []client.BlockEvents) error {
+		chunks = append(chunks, heightRange{start, end})
+		total += len(blockEvents)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{100, 119}, {120, 139}, {140, 149}}, chunks)
+	require.Equal(t, 10, total)
+}
+
+func TestSporkCacheRangeLimit(t *testing.T) {
+	backend := &countingFlowClient{headFlowClient: headFlowClient{head: 10000}}
+	cache := newTestSporkCache(t, backend)
+	_, err := cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 2000)
+	require.Nil(t, err)
+
+	// a cached range is bounded like a fetched one
+	_, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 2001)
+	require.True(t, errors.Is(err, ErrRangeTooLarge), "unexpected error %v", err)
+	err = cache.StreamEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 2001, func(uint64, uint64, []client.BlockEvents) error {
+		return nil
+	})
+	require.True(t, errors.Is(err, ErrRangeTooLarge), "unexpected error %v", err)
+	require.Len(t, backend.fetched, 1)
+}
+
+func TestSporkCacheSeparatesNetworks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	mainnet := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}}
+	cache, err := NewSporkCache(mainnet, path, "mainnet", 2000, 20)
+	require.Nil(t, err)
+	_, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	require.Nil(t, err)
+	require.Nil(t, cache.Close())
+
+	// the same file in front of another network fetches its own events
+	testnet := &countingFlowClient{headFlowClient: headFlowClient{head: 1000}}
+	cache, err = NewSporkCache(testnet, path, "testnet", 2000, 20)
+	require.Nil(t, err)
+	defer cache.Close()
+	_, err = cache.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 100, 149)
+	require.Nil(t, err)
+	require.Equal(t, []heightRange{{100, 149}}, testnet.fetched)
+
+	_, err = NewSporkCache(testnet, filepath.Join(t.TempDir(), "cache.db"), "", 2000, 20)
+	require.NotNil(t, err)
+}
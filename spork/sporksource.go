@@ -0,0 +1,138 @@
+/**
+ * spork/sporksource.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// embeddedSporks is a snapshot of the flow network config, used when nothing else is reachable
+//
+//go:embed sporks.json
+var embeddedSporks []byte
+
+// SporkSource loads the spork list of a network stage.
+// Sources accept both the flow network config and a plain JSON list of sporks, see ParseSporkList.
+type SporkSource interface {
+	String() string
+	Load(stage string) ([]Spork, error)
+}
+
+// URLSporkSource loads the spork list over http
+type URLSporkSource struct {
+	URL string
+}
+
+func (source *URLSporkSource) String() string {
+	return source.URL
+}
+
+func (source *URLSporkSource) Load(stage string) ([]Spork, error) {
+	resp, err := http.Get(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", source.URL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSporkList(data, stage)
+}
+
+// FileSporkSource loads the spork list from a local JSON file
+type FileSporkSource struct {
+	Path string
+}
+
+func (source *FileSporkSource) String() string {
+	return "file:" + source.Path
+}
+
+func (source *FileSporkSource) Load(stage string) ([]Spork, error) {
+	data, err := ioutil.ReadFile(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSporkList(data, stage)
+}
+
+// EmbeddedSporkSource loads the spork list compiled into the binary, it may lag behind the network
+type EmbeddedSporkSource struct{}
+
+func (source *EmbeddedSporkSource) String() string {
+	return "embedded"
+}
+
+func (source *EmbeddedSporkSource) Load(stage string) ([]Spork, error) {
+	return ParseSporkList(embeddedSporks, stage)
+}
+
+// FallbackSporkSource tries its sources in order and returns the first spork list loaded
+type FallbackSporkSource []SporkSource
+
+func (sources FallbackSporkSource) String() string {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.String())
+	}
+	return strings.Join(names, ",")
+}
+
+func (sources FallbackSporkSource) Load(stage string) ([]Spork, error) {
+	err := errors.New("no spork source")
+	for _, source := range sources {
+		var sporkList []Spork
+		sporkList, err = source.Load(stage)
+		if err == nil {
+			return sporkList, nil
+		}
+		log.Error("SporkSource: failed to load sporks from ", source, ": ", err)
+	}
+	return nil, err
+}
+
+// DefaultSporkSource reads NetworkConfigURL and falls back to the embedded snapshot
+func DefaultSporkSource() SporkSource {
+	return FallbackSporkSource{&URLSporkSource{URL: NetworkConfigURL}, &EmbeddedSporkSource{}}
+}
+
+// NewSporkSource selects a source from location: an http(s) URL, "embedded" or a local file path.
+// Empty location selects DefaultSporkSource.
+func NewSporkSource(location string) SporkSource {
+	switch {
+	case location == "":
+		return DefaultSporkSource()
+	case location == "embedded":
+		return &EmbeddedSporkSource{}
+	case strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://"):
+		return &URLSporkSource{URL: location}
+	default:
+		return &FileSporkSource{Path: strings.TrimPrefix(location, "file://")}
+	}
+}
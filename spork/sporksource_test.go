@@ -0,0 +1,100 @@
+package spork
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSporkList = `[
+  {"name": "mainnet15", "rootHeight": 21291692, "accessNode": "access.mainnet.nodes.onflow.org:9000"},
+  {"name": "mainnet14", "rootHeight": 19050753, "accessNode": "access-001.mainnet14.nodes.onflow.org:9000"}
+]`
+
+const testNetworkConfig = `{"networks": {"testnet": {
+  "testnet2": {"id": 2, "name": "testnet2", "rootHeight": "200", "accessNodes": []},
+  "testnet1": {"id": 1, "name": "testnet1", "rootHeight": "100", "accessNodes": ["access-001.testnet1:9000", "access-002.testnet1:9000"]}
+}}}`
+
+func TestParseSporkList(t *testing.T) {
+	sporkList, err := ParseSporkList([]byte(testSporkList), "mainnet")
+	require.Nil(t, err)
+	require.Len(t, sporkList, 2)
+	require.Equal(t, uint64(19050753), sporkList[0].RootHeight)
+	require.Equal(t, "access.mainnet.nodes.onflow.org:9000", sporkList[1].AccessNode)
+	require.Equal(t, []string{"access.mainnet.nodes.onflow.org:9000"}, sporkList[1].AccessNodes)
+
+	sporkList, err = ParseSporkList([]byte(testNetworkConfig), "testnet")
+	require.Nil(t, err)
+	require.Equal(t, []Spork{
+		{ID: 1, Name: "testnet1", RootHeight: 100, AccessNode: "access-001.testnet1:9000", AccessNodes: []string{"access-001.testnet1:9000", "access-002.testnet1:9000"}},
+		{ID: 2, Name: "testnet2", RootHeight: 200, AccessNode: TestnetEndpoints, AccessNodes: []string{TestnetEndpoints}},
+	}, sporkList)
+
+	_, err = ParseSporkList([]byte(testNetworkConfig), "mainnet")
+	require.NotNil(t, err)
+}
+
+func TestEmbeddedSporkSource(t *testing.T) {
+	sporkList, err := (&EmbeddedSporkSource{}).Load("mainnet")
+	require.Nil(t, err)
+	require.Greater(t, len(sporkList), 20)
+	for i := 1; i < len(sporkList); i++ {
+		require.Greater(t, sporkList[i].RootHeight, sporkList[i-1].RootHeight)
+	}
+	require.Equal(t, "access.mainnet.nodes.onflow.org:9000", sporkList[len(sporkList)-1].AccessNode)
+}
+
+func TestFileSporkSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spork.json")
+	require.Nil(t, ioutil.WriteFile(path, []byte(testSporkList), 0644))
+
+	source := NewSporkSource(path)
+	require.IsType(t, &FileSporkSource{}, source)
+	sporkList, err := source.Load("mainnet")
+	require.Nil(t, err)
+	require.Len(t, sporkList, 2)
+
+	_, err = NewSporkSource(filepath.Join(t.TempDir(), "missing.json")).Load("mainnet")
+	require.NotNil(t, err)
+}
+
+func TestURLSporkSourceFallback(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testNetworkConfig))
+	}))
+	defer healthy.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	source := NewSporkSource(healthy.URL)
+	require.IsType(t, &URLSporkSource{}, source)
+	sporkList, err := source.Load("testnet")
+	require.Nil(t, err)
+	require.Len(t, sporkList, 2)
+
+	_, err = NewSporkSource(broken.URL).Load("testnet")
+	require.NotNil(t, err)
+
+	sporkList, err = FallbackSporkSource{&URLSporkSource{URL: broken.URL}, &EmbeddedSporkSource{}}.Load("mainnet")
+	require.Nil(t, err)
+	require.Greater(t, len(sporkList), 20)
+}
+
+func TestSporkStoreWithEmbeddedSource(t *testing.T) {
+	store := NewSporkStoreWithSource("mainnet", NewSporkSource("embedded"), 2000, 200)
+	defer store.Close()
+
+	ret, err := store.resolveAccessNodes(store.SporkList, 21291000, 21292000)
+	require.Nil(t, err)
+	require.Equal(t, []ResolvedAccessNodeList{
+		{Start: 21291000, End: 21291691, AccessNode: "access-001.mainnet14.nodes.onflow.org:9000", AccessNodes: []string{"access-001.mainnet14.nodes.onflow.org:9000"}},
+		{Start: 21291692, End: 21292000, AccessNode: "access-001.mainnet15.nodes.onflow.org:9000", AccessNodes: []string{"access-001.mainnet15.nodes.onflow.org:9000"}},
+	}, ret)
+}
@@ -23,43 +23,45 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/client"
 	log "github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
 )
 
+const (
+	// nodeCooldown is how long a failing access node is skipped before it is pinged again
+	nodeCooldown = 30 * time.Second
+
+	// clientIdleTimeout closes the pooled clients of access nodes no longer queried
+	clientIdleTimeout = 5 * time.Minute
+
+	// clientHealthCheckInterval is how often a pooled client is pinged before use
+	clientHealthCheckInterval = 30 * time.Second
+)
+
+// ErrRangeTooLarge rejects a query over more blocks than maxQueryBlocks
+var ErrRangeTooLarge = errors.New("total blocks is greater than maxQueryBlocks")
+
 var (
 	NetworkConfigURL = "https://raw.githubusercontent.com/onflow/flow/master/sporks.json"
 	TestnetEndpoints = "access.devnet.nodes.onflow.org:9000"
 )
 
 type Spork struct {
-	ID         float64 `json:"-"`
-	Name       string  `json:"name"`
-	RootHeight uint64  `json:"rootHeight"`
-	AccessNode string  `json:"accessNode"`
+	ID          float64  `json:"-"`
+	Name        string   `json:"name"`
+	RootHeight  uint64   `json:"rootHeight"`
+	AccessNode  string   `json:"accessNode"`
+	AccessNodes []string `json:"accessNodes,omitempty"`
 }
 
+// ReadJSONFromUrl reads a spork list from the given url, see URLSporkSource
 func ReadJSONFromUrl(url string) ([]Spork, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-	var sporkList []Spork
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(resp.Body)
-	respByte := buf.Bytes()
-	if err := json.Unmarshal(respByte, &sporkList); err != nil {
-		return nil, err
-	}
-
-	return sporkList, nil
+	return (&URLSporkSource{URL: url}).Load("")
 }
 
 type FlowNetworkConfig struct {
@@ -75,14 +77,33 @@ type StageNetworkConfig struct {
 
 // ReadFlowNetworkConfigFromUrl reads the flow network config from the given url.
 func ReadFlowNetworkConfigFromUrl(stage string) ([]Spork, error) {
-	resp, err := http.Get(NetworkConfigURL)
-	if err != nil {
-		return nil, err
+	return (&URLSporkSource{URL: NetworkConfigURL}).Load(stage)
+}
+
+// ParseSporkList parses either a flow network config, of which the sporks of stage are kept,
+// or a plain JSON list of sporks.
+func ParseSporkList(data []byte, stage string) ([]Spork, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var sporkList []Spork
+		if err := json.Unmarshal(data, &sporkList); err != nil {
+			return nil, err
+		}
+		for i := range sporkList {
+			if len(sporkList[i].AccessNodes) == 0 && sporkList[i].AccessNode != "" {
+				sporkList[i].AccessNodes = []string{sporkList[i].AccessNode}
+			} else if sporkList[i].AccessNode == "" && len(sporkList[i].AccessNodes) > 0 {
+				sporkList[i].AccessNode = sporkList[i].AccessNodes[0]
+			}
+		}
+		sort.Slice(sporkList, func(i, j int) bool {
+			return sporkList[i].RootHeight < sporkList[j].RootHeight
+		})
+		return sporkList, nil
 	}
-	defer resp.Body.Close()
+
 	networks := FlowNetworkConfig{}
-	err = json.NewDecoder(resp.Body).Decode(&networks)
-	if err != nil {
+	if err := json.Unmarshal(data, &networks); err != nil {
 		return nil, err
 	}
 	if networks.Networks == nil {
@@ -98,18 +119,20 @@ func ReadFlowNetworkConfigFromUrl(stage string) ([]Spork, error) {
 		if err != nil {
 			return nil, err
 		}
-		var accessNode string
-		if len(c.AccessNodes) > 0 {
-			accessNode = c.AccessNodes[0]
+		accessNodes := c.AccessNodes
+		if stage == "testnet" && len(accessNodes) == 0 {
+			accessNodes = []string{TestnetEndpoints}
 		}
-		if stage == "testnet" && accessNode == "" {
-			accessNode = TestnetEndpoints
+		var accessNode string
+		if len(accessNodes) > 0 {
+			accessNode = accessNodes[0]
 		}
 		sporkList = append(sporkList, Spork{
-			ID:         c.ID,
-			Name:       c.Name,
-			RootHeight: uint64(rootHeight),
-			AccessNode: accessNode,
+			ID:          c.ID,
+			Name:        c.Name,
+			RootHeight:  uint64(rootHeight),
+			AccessNode:  accessNode,
+			AccessNodes: accessNodes,
 		})
 	}
 	sort.Slice(sporkList, func(i, j int) bool {
@@ -125,15 +148,39 @@ type SporkStore struct {
 
 	stage string
 
+	source SporkSource
+
 	readClient *client.Client
 
+	readNode string
+
+	balancer *NodeBalancer
+
+	pool *ClientPool
+
 	maxQueryBlocks uint64
 
 	queryBatchSize uint64
+
+	queryConcurrency int
+
+	timeouts Timeouts
+
+	retryPolicy RetryPolicy
+
+	// blockTimes caches the block timestamps read by QueryBlockHeightByTime
+	blockTimes *blockTimeIndex
 }
 
 func NewSporkStore(stage string, maxQueryBlocks uint64, queryBatchSize uint64) *SporkStore {
-	ss := &SporkStore{stage: stage, maxQueryBlocks: maxQueryBlocks, queryBatchSize: queryBatchSize}
+	return NewSporkStoreWithSource(stage, DefaultSporkSource(), maxQueryBlocks, queryBatchSize)
+}
+
+// NewSporkStoreWithSource creates a SporkStore reading its spork list from source
+func NewSporkStoreWithSource(stage string, source SporkSource, maxQueryBlocks uint64, queryBatchSize uint64) *SporkStore {
+	balancer, _ := NewNodeBalancer(BalanceRoundRobin, nodeCooldown)
+	pool := NewClientPool(clientIdleTimeout, clientHealthCheckInterval)
+	ss := &SporkStore{stage: stage, source: source, balancer: balancer, pool: pool, maxQueryBlocks: maxQueryBlocks, queryBatchSize: queryBatchSize, queryConcurrency: 1, retryPolicy: DefaultRetryPolicy, blockTimes: newBlockTimeIndex(maxBlockTimeSamples)}
 	err := ss.SyncSpork()
 	if err != nil {
 		panic(err)
@@ -148,60 +195,99 @@ func NewSporkStore(stage string, maxQueryBlocks uint64, queryBatchSize uint64) *
 
 func (ss *SporkStore) String() string {
 	// with basic information with sporkList
-	return fmt.Sprintf("SporkStore{stage: %s, maxQueryBlocks: %d, queryBatchSize: %d, sporkList: %v}\n", ss.stage, ss.maxQueryBlocks, ss.queryBatchSize, ss.SporkList)
+	return fmt.Sprintf("SporkStore{stage: %s, source: %s, maxQueryBlocks: %d, queryBatchSize: %d, sporkList: %v}\n", ss.stage, ss.source, ss.maxQueryBlocks, ss.queryBatchSize, ss.sporks())
 }
 
-func (ss *SporkStore) SyncSpork() error {
+// sporks returns the current spork list. SyncSpork replaces the list instead of changing it in place,
+// so the returned one stays consistent without the lock.
+func (ss *SporkStore) sporks() []Spork {
 	ss.Lock()
 	defer ss.Unlock()
-	var err error = nil
-	ss.SporkList, err = ReadFlowNetworkConfigFromUrl(ss.stage)
-	log.Info("sync", ss.SporkList)
-	return err
+	return ss.SporkList
 }
 
-func (ss *SporkStore) resolveAccessNodes(start uint64, end uint64) ([]ResolvedAccessNodeList, error) {
+// SyncSpork reloads the spork list. The source is read without the lock, so a slow one does not block the queries.
+func (ss *SporkStore) SyncSpork() error {
+	sporkList, err := ss.source.Load(ss.stage)
+	if err != nil {
+		return err
+	}
+	if len(sporkList) == 0 {
+		return fmt.Errorf("no spork found for stage %s", ss.stage)
+	}
+	log.Info("sync", sporkList)
+
+	ss.Lock()
+	// a new spork has started, the read client must follow the live access node
+	liveNodeChanged := len(ss.SporkList) > 0 && ss.SporkList[len(ss.SporkList)-1].AccessNode != sporkList[len(sporkList)-1].AccessNode
+	ss.SporkList = sporkList
+	followLiveNode := liveNodeChanged && ss.readClient != nil
+	ss.Unlock()
+
+	if followLiveNode {
+		log.Info("live access node changed to ", sporkList[len(sporkList)-1].AccessNode)
+		return ss.newReadClient()
+	}
+	return nil
+}
+
+// resolveAccessNodes splits [start, end] into one segment per spork of sporkList, a snapshot taken with sporks
+func (ss *SporkStore) resolveAccessNodes(sporkList []Spork, start uint64, end uint64) ([]ResolvedAccessNodeList, error) {
 	if end-start > ss.maxQueryBlocks {
-		return nil, errors.New("total blocks is greater than maxQueryBlocks")
+		return nil, ErrRangeTooLarge
 	}
 
 	result := make([]ResolvedAccessNodeList, 0)
 
-	startNodeIdx, err := ss.locateNode(start)
+	startNodeIdx, err := locateNode(sporkList, start)
 	if err != nil {
 		return nil, err
 	}
 
-	endNodeIdx, err := ss.locateNode(end)
+	endNodeIdx, err := locateNode(sporkList, end)
 	if err != nil {
 		return nil, err
 	}
 
-	if startNodeIdx == endNodeIdx {
-		result = append(result, ResolvedAccessNodeList{Start: start, End: end, AccessNode: ss.SporkList[startNodeIdx].AccessNode})
-	} else {
-		result = append(result, ResolvedAccessNodeList{Start: start, End: ss.SporkList[endNodeIdx].RootHeight - 1, AccessNode: ss.SporkList[startNodeIdx].AccessNode})
-		result = append(result, ResolvedAccessNodeList{Start: ss.SporkList[endNodeIdx].RootHeight, End: end, AccessNode: ss.SporkList[endNodeIdx].AccessNode})
+	// one segment per spork, the middle sporks are covered from root height to the next root height
+	for idx := startNodeIdx; idx <= endNodeIdx; idx++ {
+		segment := ResolvedAccessNodeList{
+			Start:       start,
+			End:         end,
+			AccessNode:  sporkList[idx].AccessNode,
+			AccessNodes: sporkList[idx].AccessNodes,
+		}
+		if idx > startNodeIdx {
+			segment.Start = sporkList[idx].RootHeight
+		}
+		if idx < endNodeIdx {
+			segment.End = sporkList[idx+1].RootHeight - 1
+		}
+		result = append(result, segment)
 	}
 
 	return result, nil
 }
 
-func (ss *SporkStore) locateNode(index uint64) (int, error) {
+// locateNode returns the index of the spork of sporkList holding the block at index
+func locateNode(sporkList []Spork, index uint64) (int, error) {
+	if len(sporkList) == 0 {
+		return 0, errors.New("spork list is empty")
+	}
 	left := 0
-	right := len(ss.SporkList) - 1
+	right := len(sporkList) - 1
 	var mid, ret int
 	for left < right-1 {
 		mid = (left + (right-left)/2)
-		if ss.SporkList[mid].RootHeight > index {
+		if sporkList[mid].RootHeight > index {
 			right = mid
 		} else {
 			left = mid + 1
 		}
 	}
-	if index < ss.SporkList[left].RootHeight {
+	if index < sporkList[left].RootHeight {
 		ret = left - 1
-	} else if index < ss.SporkList[right].RootHeight {
+	} else if index < sporkList[right].RootHeight {
 		ret = right - 1
 	} else {
 		ret = right
@@ -212,78 +298,395 @@ func (ss *SporkStore) locateNode(index uint64) (int, error) {
 	return ret, nil
 }
 
+// newReadClient connects to an access node of the live spork and swaps it in for the previous read client,
+// the connection is made without the lock
 func (ss *SporkStore) newReadClient() error {
-	log.Info("new read client")
-	flowClient, err := client.New(ss.SporkList[len(ss.SporkList)-1].AccessNode, grpc.WithInsecure(), grpc.WithMaxMsgSize(40e6))
+	ss.Lock()
+	liveSpork := ss.SporkList[len(ss.SporkList)-1]
+	balancer := ss.balancer
+	ss.Unlock()
+
+	accessNodes := liveSpork.AccessNodes
+	if len(accessNodes) == 0 {
+		accessNodes = []string{liveSpork.AccessNode}
+	}
+	readNode := balancer.Order(accessNodes)[0]
+	log.Info("new read client ", readNode)
+	flowClient, err := ss.pool.Get(context.Background(), readNode)
 	if err != nil {
 		return err
 	}
-	ss.readClient = flowClient
+
+	ss.Lock()
+	previousNode, previousClient := ss.readNode, ss.readClient
+	ss.readNode, ss.readClient = readNode, flowClient
+	ss.Unlock()
+	if previousClient != nil {
+		ss.pool.Release(previousNode, previousClient)
+	}
 	return nil
 }
 
-func (ss *SporkStore) checkReaderHealthy() error {
-	ctx := context.Background()
+// checkReaderHealthy pings the read node with a reference of its own, so a concurrent failover cannot close
+// the client mid-ping, and fails over to the next access node of the live spork when it is not healthy
+func (ss *SporkStore) checkReaderHealthy(ctx context.Context) error {
+	ss.Lock()
+	readNode, readClient, balancer := ss.readNode, ss.readClient, ss.balancer
+	ss.Unlock()
 	log.Info("Start to ping")
 
-	err := ss.readClient.Ping(ctx)
+	flowClient, err := ss.pool.Get(ctx, readNode)
+	if err == nil {
+		if err = flowClient.Ping(ctx); err != nil {
+			ss.pool.Discard(readNode, flowClient)
+		}
+		ss.pool.Release(readNode, flowClient)
+	}
+	// a cancelled request says nothing about the node
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
 	if err != nil {
 		log.Error("client is not healthy ", err)
-		// close readClient
-		ss.readClient.Close()
+		// fail over to the next access node of the live spork
+		balancer.ReportFailure(readNode)
+		// drop the broken connection from the pool, newReadClient releases it
+		ss.pool.Discard(readNode, readClient)
 		return ss.newReadClient()
 	}
 	return nil
 }
 
-func (ss *SporkStore) QueryLatestBlockHeight() (uint64, error) {
+// QueryLatestBlockHeight reads the sealed head from the read client without holding the lock,
+// so a slow access node does not block the other requests
+func (ss *SporkStore) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
 	ss.Lock()
-	defer ss.Unlock()
-	ss.checkReaderHealthy()
-	ctx := context.Background()
-	header, err := ss.readClient.GetLatestBlockHeader(ctx, true)
+	timeouts := ss.timeouts
+	ss.Unlock()
+	ctx, cancel := timeouts.apply(ctx)
+	defer cancel()
+	if err := ss.checkReaderHealthy(ctx); err != nil {
+		return 0, err
+	}
+
+	// a reference of its own keeps the client open if a concurrent failover releases it
+	ss.Lock()
+	readNode := ss.readNode
+	ss.Unlock()
+	readClient, err := ss.pool.Get(ctx, readNode)
+	if err != nil {
+		return 0, err
+	}
+	defer ss.pool.Release(readNode, readClient)
+	header, err := readClient.GetLatestBlockHeader(ctx, true)
 	if err != nil {
 		return 0, err
 	}
 	return header.Height, err
 }
 
-func (ss *SporkStore) QueryEventByBlockRange(event string, start uint64, end uint64) ([]client.BlockEvents, error) {
-	ctx := context.Background()
+// QueryBlockHeightByTime finds the spork of timestamp from the root block timestamps,
+// then searches the block headers of that spork only
+func (ss *SporkStore) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	ss.Lock()
+	balancer := ss.balancer
+	timeouts := ss.timeouts
+	sporkList := ss.SporkList
+	ss.Unlock()
 
-	events := make([]client.BlockEvents, 0)
+	ctx, cancel := timeouts.apply(ctx)
+	defer cancel()
+
+	latestHeight, err := ss.QueryLatestBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the first spork whose root block is not older than timestamp
+	var searchErr error
+	idx := sort.Search(len(sporkList), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		rootTimestamp, err := ss.blockTimes.timestamp(ctx, sporkList[i].RootHeight, ss.blockTimestampFetcher(balancer, sporkList[i]))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return !rootTimestamp.Before(timestamp)
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	// timestamp is older than the earliest supported block
+	if idx == 0 {
+		return sporkList[0].RootHeight, nil
+	}
 
-	resolvedAccessNodeList, err := ss.resolveAccessNodes(uint64(start), uint64(end))
+	spork := sporkList[idx-1]
+	end := latestHeight
+	if idx < len(sporkList) {
+		end = sporkList[idx].RootHeight - 1
+	}
+	return ss.blockTimes.search(ctx, timestamp, spork.RootHeight+1, end, ss.blockTimestampFetcher(balancer, spork))
+}
+
+// QueryBlockHeaderByHeight reads the header from the access nodes of the spork holding height,
+// historical blocks are not served by the live spork
+func (ss *SporkStore) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	spork, balancer, timeouts, err := ss.sporkAt(height)
 	if err != nil {
 		return nil, err
 	}
+	ctx, cancel := timeouts.apply(ctx)
+	defer cancel()
+	return ss.queryBlockHeader(ctx, balancer, spork, height)
+}
 
-	for _, node := range resolvedAccessNodeList {
-		flowClient, err := client.New(node.AccessNode, grpc.WithInsecure(), grpc.WithMaxMsgSize(140e6))
-		defer flowClient.Close()
-		defer log.Info("close client from:", node.AccessNode)
+// QueryTransaction reads the transaction from the access nodes of the spork holding its block
+func (ss *SporkStore) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error) {
+	spork, balancer, timeouts, err := ss.sporkAt(height)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := timeouts.apply(ctx)
+	defer cancel()
 
+	var info *TransactionInfo
+	err = ss.withSporkClient(ctx, balancer, spork, func(flowClient *client.Client) error {
+		tx, err := flowClient.GetTransaction(ctx, txID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		result, err := flowClient.GetTransactionResult(ctx, txID)
+		if err != nil {
+			return err
+		}
+		info = NewTransactionInfo(tx, result)
+		return nil
+	})
+	return info, err
+}
+
+// sporkAt returns the spork holding height with the settings to query it, all read from one snapshot
+func (ss *SporkStore) sporkAt(height uint64) (Spork, *NodeBalancer, Timeouts, error) {
+	ss.Lock()
+	sporkList := ss.SporkList
+	balancer := ss.balancer
+	timeouts := ss.timeouts
+	ss.Unlock()
+
+	idx, err := locateNode(sporkList, height)
+	if err != nil {
+		return Spork{}, nil, Timeouts{}, err
+	}
+	return sporkList[idx], balancer, timeouts, nil
+}
+
+// queryBlockHeader reads a block header from the access nodes of spork
+func (ss *SporkStore) queryBlockHeader(ctx context.Context, balancer *NodeBalancer, spork Spork, height uint64) (*flow.BlockHeader, error) {
+	var header *flow.BlockHeader
+	err := ss.withSporkClient(ctx, balancer, spork, func(flowClient *client.Client) error {
+		var err error
+		header, err = flowClient.GetBlockHeaderByHeight(ctx, height)
+		return err
+	})
+	return header, err
+}
+
+// withSporkClient runs call with the clients of the access nodes of spork in balancer order,
+// until one succeeds or fails with an error another node would return as well
+func (ss *SporkStore) withSporkClient(ctx context.Context, balancer *NodeBalancer, spork Spork, call func(flowClient *client.Client) error) error {
+	accessNodes := spork.AccessNodes
+	if len(accessNodes) == 0 {
+		accessNodes = []string{spork.AccessNode}
+	}
+	var err error
+	for _, accessNode := range balancer.Order(accessNodes) {
+		var flowClient *client.Client
+		flowClient, err = ss.pool.Get(ctx, accessNode)
+		if err != nil {
+			balancer.ReportFailure(accessNode)
+			continue
+		}
+		err = call(flowClient)
+		ss.pool.Release(accessNode, flowClient)
+		if err == nil {
+			return nil
 		}
+		if ctx.Err() != nil || classifyError(err) == actionFail {
+			break
+		}
+		balancer.ReportFailure(accessNode)
+	}
+	return err
+}
+
+// blockTimestampFetcher reads block timestamps from the access nodes of spork
+func (ss *SporkStore) blockTimestampFetcher(balancer *NodeBalancer, spork Spork) blockTimestampFunc {
+	return func(ctx context.Context, height uint64) (time.Time, error) {
+		header, err := ss.queryBlockHeader(ctx, balancer, spork, height)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return header.Timestamp, nil
+	}
+}
 
+func (ss *SporkStore) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	events := make([]client.BlockEvents, 0)
+	err := ss.StreamEventByBlockRange(ctx, eventTypes, start, end, func(_ uint64, _ uint64, ret []client.BlockEvents) error {
+		events = append(events, ret...)
+		return nil
+	})
+	if err != nil && !IsPartialError(err) {
+		return nil, err
+	}
+	return events, err
+}
+
+func (ss *SporkStore) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler BlockEventsHandler) error {
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+
+	ss.Lock()
+	sporkList := ss.SporkList
+	balancer := ss.balancer
+	timeouts := ss.timeouts
+	retryPolicy := ss.retryPolicy
+	ss.Unlock()
+
+	resolvedAccessNodeList, err := ss.resolveAccessNodes(sporkList, uint64(start), uint64(end))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeouts.apply(WithRetryPolicy(ctx, retryPolicy))
+	defer cancel()
+
+	// in partial mode the failed ranges of every spork are reported together once all of them are done
+	failedRanges := make([]FailedRange, 0)
+	for _, node := range resolvedAccessNodeList {
+		accessNodes := node.AccessNodes
+		if len(accessNodes) == 0 {
+			accessNodes = []string{node.AccessNode}
+		}
 		tmpQueryBatchSize := ss.queryBatchSize
-		ret, err := IterQueryEventByBlockRange(ctx, flowClient, event, node.Start, node.End, tmpQueryBatchSize)
+		err = forEachBatch(ctx, node.Start, node.End, tmpQueryBatchSize, ss.getQueryConcurrency(), func(ctx context.Context, start uint64, end uint64) ([]client.BlockEvents, error) {
+			return ss.fetchBatch(ctx, balancer, accessNodes, eventTypes, start, end)
+		}, handler)
+		if err != nil && !IsPartialError(err) {
+			return err
+		}
+		failedRanges = append(failedRanges, failedRangesOf(err)...)
+	}
+	if len(failedRanges) > 0 {
+		return newPartialError(failedRanges)
+	}
+	return nil
+}
+
+// fetchBatch fetches one batch from the access nodes of a spork in balancer order,
+// failing over to the next node when one returns an error
+func (ss *SporkStore) fetchBatch(ctx context.Context, balancer *NodeBalancer, accessNodes []string, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	var err error
+	orderedNodes := balancer.Order(accessNodes)
+	for i, accessNode := range orderedNodes {
+		// while another node is left, failing over beats waiting to retry on this one or skipping blocks
+		nodeCtx := ctx
+		if i < len(orderedNodes)-1 {
+			policy := retryPolicyFrom(ctx)
+			policy.MaxRetries = 0
+			nodeCtx = WithPartialResults(WithRetryPolicy(ctx, policy), false)
+		}
+
+		// the client is taken per batch, one failing its ping is dropped before another batch picks it up
+		var flowClient *client.Client
+		flowClient, err = ss.pool.Get(ctx, accessNode)
 		if err != nil {
+			balancer.ReportFailure(accessNode)
+			continue
+		}
+		if balancer.ShouldPing(accessNode) {
+			if err = flowClient.Ping(ctx); err != nil {
+				balancer.ReportFailure(accessNode)
+				ss.pool.Discard(accessNode, flowClient)
+				ss.pool.Release(accessNode, flowClient)
+				continue
+			}
+			balancer.MarkHealthy(accessNode)
+		}
+
+		begin := time.Now()
+		var events []client.BlockEvents
+		events, err = IterQueryEventByBlockRange(nodeCtx, flowClient, eventTypes, start, end, ss.queryBatchSize)
+		ss.pool.Release(accessNode, flowClient)
+		if err == nil {
+			balancer.ReportSuccess(accessNode, time.Since(begin))
+			return events, nil
+		}
+		// the last node skipped the blocks it could not fetch
+		if IsPartialError(err) {
+			return events, err
+		}
+		// another node would reject the request as well
+		if ctx.Err() != nil || classifyError(err) == actionFail {
 			return nil, err
 		}
-		events = append(events, ret...)
+		log.Error("SporkStore: access node ", accessNode, " failed for ", start, " - ", end, ", failing over: ", err)
+		balancer.ReportFailure(accessNode)
+	}
+	return nil, err
+}
 
+// SetTimeouts bounds every request and every access node call in it
+func (ss *SporkStore) SetTimeouts(timeouts Timeouts) {
+	ss.Lock()
+	defer ss.Unlock()
+	ss.timeouts = timeouts
+}
+
+// SetRetryPolicy sets how failed batches are retried, a node with another one left fails over instead
+func (ss *SporkStore) SetRetryPolicy(policy RetryPolicy) {
+	ss.Lock()
+	defer ss.Unlock()
+	ss.retryPolicy = policy
+}
+
+// SetBalanceStrategy selects how the access nodes of a spork share the batches, see BalanceRoundRobin and BalanceLatency
+func (ss *SporkStore) SetBalanceStrategy(strategy string) error {
+	balancer, err := NewNodeBalancer(strategy, nodeCooldown)
+	if err != nil {
+		return err
 	}
-	return events, nil
+	ss.Lock()
+	defer ss.Unlock()
+	ss.balancer = balancer
+	return nil
+}
+
+// SetQueryConcurrency sets how many batches are fetched at once, 1 fetches them one after another
+func (ss *SporkStore) SetQueryConcurrency(concurrency int) {
+	ss.Lock()
+	defer ss.Unlock()
+	ss.queryConcurrency = concurrency
+}
+
+func (ss *SporkStore) getQueryConcurrency() int {
+	ss.Lock()
+	defer ss.Unlock()
+	return ss.queryConcurrency
 }
 
 // Close connection
 func (ss *SporkStore) Close() error {
+	ss.Lock()
+	defer ss.Unlock()
 	if ss.readClient != nil {
-		err := ss.readClient.Close()
+		ss.pool.Release(ss.readNode, ss.readClient)
+		ss.readClient = nil
 		log.Info("close read client")
-		return err
 	}
-	return nil
+	return ss.pool.Close()
 }
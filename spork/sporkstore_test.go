@@ -1,17 +1,357 @@
 package spork
 
 import (
+	"context"
+	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// newStaticSporkStore returns a SporkStore over a fixed spork list without touching the network
+func newStaticSporkStore() *SporkStore {
+	return &SporkStore{
+		SporkList: []Spork{
+			{Name: "spork1", RootHeight: 100, AccessNode: "access-1"},
+			{Name: "spork2", RootHeight: 200, AccessNode: "access-2"},
+			{Name: "spork3", RootHeight: 300, AccessNode: "access-3"},
+			{Name: "spork4", RootHeight: 400, AccessNode: "access-4"},
+			{Name: "spork5", RootHeight: 500, AccessNode: "access-5"},
+		},
+		maxQueryBlocks: 1000,
+	}
+}
+
+func TestSporkStoreLocateNode(t *testing.T) {
+	ss := newStaticSporkStore()
+	cases := []struct {
+		height uint64
+		idx    int
+		err    bool
+	}{
+		{height: 99, err: true},
+		{height: 100, idx: 0},
+		{height: 199, idx: 0},
+		{height: 200, idx: 1},
+		{height: 250, idx: 1},
+		{height: 299, idx: 1},
+		{height: 300, idx: 2},
+		{height: 399, idx: 2},
+		{height: 400, idx: 3},
+		{height: 500, idx: 4},
+		{height: 100000, idx: 4},
+	}
+	for _, c := range cases {
+		idx, err := locateNode(ss.SporkList, c.height)
+		if c.err {
+			require.NotNil(t, err, "height %d", c.height)
+			continue
+		}
+		require.Nil(t, err, "height %d", c.height)
+		require.Equal(t, c.idx, idx, "height %d", c.height)
+	}
+
+	_, err := locateNode(nil, 100)
+	require.NotNil(t, err)
+}
+
+func TestSporkStoreResolveAccessNodes(t *testing.T) {
+	ss := newStaticSporkStore()
+	cases := []struct {
+		name     string
+		start    uint64
+		end      uint64
+		expected []ResolvedAccessNodeList
+		err      bool
+	}{
+		{
+			name:     "single spork",
+			start:    210,
+			end:      290,
+			expected: []ResolvedAccessNodeList{{Start: 210, End: 290, AccessNode: "access-2"}},
+		},
+		{
+			name:  "two sporks",
+			start: 150,
+			end:   250,
+			expected: []ResolvedAccessNodeList{
+				{Start: 150, End: 199, AccessNode: "access-1"},
+				{Start: 200, End: 250, AccessNode: "access-2"},
+			},
+		},
+		{
+			name:  "spans intermediate sporks",
+			start: 150,
+			end:   450,
+			expected: []ResolvedAccessNodeList{
+				{Start: 150, End: 199, AccessNode: "access-1"},
+				{Start: 200, End: 299, AccessNode: "access-2"},
+				{Start: 300, End: 399, AccessNode: "access-3"},
+				{Start: 400, End: 450, AccessNode: "access-4"},
+			},
+		},
+		{
+			name:  "starts on a root height",
+			start: 300,
+			end:   500,
+			expected: []ResolvedAccessNodeList{
+				{Start: 300, End: 399, AccessNode: "access-3"},
+				{Start: 400, End: 499, AccessNode: "access-4"},
+				{Start: 500, End: 500, AccessNode: "access-5"},
+			},
+		},
+		{
+			name:  "before the first spork",
+			start: 50,
+			end:   150,
+			err:   true,
+		},
+		{
+			name:  "more than maxQueryBlocks",
+			start: 100,
+			end:   1200,
+			err:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ret, err := ss.resolveAccessNodes(ss.SporkList, c.start, c.end)
+			if c.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, c.expected, ret)
+		})
+	}
+}
+
+// newMultiNodeSporkStore returns a SporkStore with a single spork served by accessNodes
+func newMultiNodeSporkStore(t *testing.T, strategy string, accessNodes ...string) *SporkStore {
+	balancer, err := NewNodeBalancer(strategy, time.Minute)
+	require.Nil(t, err)
+	ss := &SporkStore{
+		SporkList:        []Spork{{Name: "spork1", RootHeight: 0, AccessNode: accessNodes[0], AccessNodes: accessNodes}},
+		balancer:         balancer,
+		pool:             NewClientPool(time.Minute, time.Minute),
+		maxQueryBlocks:   2000,
+		queryBatchSize:   100,
+		queryConcurrency: 1,
+		retryPolicy:      testRetryPolicy,
+		blockTimes:       newBlockTimeIndex(maxBlockTimeSamples),
+	}
+	t.Cleanup(func() { ss.Close() })
+	return ss
+}
+
+func TestSporkStoreFailover(t *testing.T) {
+	down := &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}
+	up := &fakeAccessNode{}
+	downAddr := newFakeAccessNodeAddr(t, down)
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, downAddr, newFakeAccessNodeAddr(t, up))
+
+	ret, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 999)
+	require.Nil(t, err)
+	require.Len(t, ret, 1000)
+	require.False(t, ss.balancer.Healthy(downAddr))
+	require.Equal(t, 10, up.calls)
+
+	// the failing node is only tried for the first batch, then moved to the back
+	downCalls := down.calls
+	_, err = ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 999)
+	require.Nil(t, err)
+	require.Equal(t, downCalls, down.calls)
+	require.Equal(t, 20, up.calls)
+}
+
+func TestSporkStoreConcurrentBatchesWithDeadNode(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	deadAddr := lis.Addr().String()
+	lis.Close()
+	up := &fakeAccessNode{}
+	upAddr := newFakeAccessNodeAddr(t, up)
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, deadAddr, upAddr)
+	// failed nodes are pinged again by the next batch picking them
+	ss.balancer, err = NewNodeBalancer(BalanceRoundRobin, 0)
+	require.Nil(t, err)
+	ss.SetQueryConcurrency(2)
+
+	// the pooled connection of the live node breaks and fails its ping
+	broken, err := ss.pool.Get(context.Background(), upAddr)
+	require.Nil(t, err)
+	ss.pool.Release(upAddr, broken)
+	broken.Close()
+	ss.balancer.ReportFailure(upAddr)
+
+	// only the two batches racing for the broken client miss, the next ones redial it
+	ret, err := ss.QueryEventByBlockRange(WithPartialResults(context.Background(), true), []string{cacheTestEvent}, 0, 399)
+	require.True(t, IsPartialError(err), "unexpected error %v", err)
+	for _, failed := range failedRangesOf(err) {
+		require.Less(t, failed.End, uint64(200))
+	}
+	require.GreaterOrEqual(t, len(ret), 200)
+	require.GreaterOrEqual(t, up.callCount(), 2)
+	require.False(t, ss.balancer.Healthy(deadAddr))
+}
+
+func TestSporkStoreAllNodesDown(t *testing.T) {
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin,
+		newFakeAccessNodeAddr(t, &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}),
+		newFakeAccessNodeAddr(t, &fakeAccessNode{err: status.Error(codes.Unavailable, "node down")}))
+
+	_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 99)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "node down")
+}
+
+func TestSporkStoreBalancesNodes(t *testing.T) {
+	first := &fakeAccessNode{}
+	second := &fakeAccessNode{}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, first), newFakeAccessNodeAddr(t, second))
+
+	_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 999)
+	require.Nil(t, err)
+	require.Equal(t, 5, first.calls)
+	require.Equal(t, 5, second.calls)
+
+	slow := &fakeAccessNode{latency: 20 * time.Millisecond}
+	fast := &fakeAccessNode{}
+	ss = newMultiNodeSporkStore(t, BalanceLatency, newFakeAccessNodeAddr(t, slow), newFakeAccessNodeAddr(t, fast))
+
+	_, err = ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 1999)
+	require.Nil(t, err)
+	require.Equal(t, 1, slow.calls, "the slow node is only measured once")
+	require.Equal(t, 19, fast.calls)
+}
+
+func TestSporkStoreSharesPooledClients(t *testing.T) {
+	node := &fakeAccessNode{}
+	addr := newFakeAccessNodeAddr(t, node)
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, addr)
+
+	flowClient, err := ss.pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	ss.pool.Release(addr, flowClient)
+
+	for i := 0; i < 3; i++ {
+		_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 199)
+		require.Nil(t, err)
+	}
+	require.Equal(t, 1, ss.pool.Len())
+
+	pooled, err := ss.pool.Get(context.Background(), addr)
+	require.Nil(t, err)
+	defer ss.pool.Release(addr, pooled)
+	require.Same(t, flowClient, pooled, "every query reuses the pooled connection")
+}
+
+func TestSporkStoreRequestTimeout(t *testing.T) {
+	node := &fakeAccessNode{latency: 50 * time.Millisecond}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, node))
+	ss.SetTimeouts(Timeouts{Request: 120 * time.Millisecond})
+
+	_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 999)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	require.Less(t, node.callCount(), 10)
+
+	// the deadline of the caller applies as well
+	ss.SetTimeouts(Timeouts{})
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+	_, err = ss.QueryEventByBlockRange(ctx, []string{cacheTestEvent}, 0, 999)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+}
+
+// staticSporkSource loads a copy of its sporks, like a source reading them again would
+type staticSporkSource []Spork
+
+func (source staticSporkSource) String() string {
+	return "static"
+}
+
+func (source staticSporkSource) Load(stage string) ([]Spork, error) {
+	return append([]Spork(nil), source...), nil
+}
+
+func TestSporkStoreSyncDuringQueries(t *testing.T) {
+	node := &fakeAccessNode{head: 199}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, node))
+	ss.source = staticSporkSource(ss.SporkList)
+
+	syncErr := make(chan error, 1)
+	go func() {
+		var err error
+		for i := 0; i < 50 && err == nil; i++ {
+			err = ss.SyncSpork()
+		}
+		syncErr <- err
+	}()
+	for i := 0; i < 5; i++ {
+		_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 199)
+		require.Nil(t, err)
+		header, err := ss.QueryBlockHeaderByHeight(context.Background(), uint64(i))
+		require.Nil(t, err)
+		require.Equal(t, uint64(i), header.Height)
+	}
+	require.Nil(t, <-syncErr)
+}
+
+func TestSporkStoreSlowHeadDoesNotBlock(t *testing.T) {
+	node := &fakeAccessNode{head: 199, headLatency: 500 * time.Millisecond}
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, newFakeAccessNodeAddr(t, node))
+	require.Nil(t, ss.newReadClient())
+	ss.source = staticSporkSource(ss.SporkList)
+
+	headErr := make(chan error, 1)
+	go func() {
+		_, err := ss.QueryLatestBlockHeight(context.Background())
+		headErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// neither the event queries nor a spork sync wait for the slow head
+	begin := time.Now()
+	_, err := ss.QueryEventByBlockRange(context.Background(), []string{cacheTestEvent}, 0, 99)
+	require.Nil(t, err)
+	require.Nil(t, ss.SyncSpork())
+	require.Less(t, time.Since(begin), 250*time.Millisecond)
+	require.Nil(t, <-headErr)
+}
+
+func TestSporkStoreReaderFailover(t *testing.T) {
+	first := &fakeAccessNode{head: 100}
+	second := &fakeAccessNode{head: 100}
+	firstAddr := newFakeAccessNodeAddr(t, first)
+	secondAddr := newFakeAccessNodeAddr(t, second)
+	ss := newMultiNodeSporkStore(t, BalanceRoundRobin, firstAddr, secondAddr)
+	require.Nil(t, ss.newReadClient())
+	require.Equal(t, firstAddr, ss.readNode)
+
+	// the connection of the read node breaks, the read moves to the next node
+	ss.readClient.Close()
+	height, err := ss.QueryLatestBlockHeight(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), height)
+	require.Equal(t, secondAddr, ss.readNode)
+	require.False(t, ss.balancer.Healthy(firstAddr))
+
+	// a failover that cannot connect is reported instead of reading from a broken client
+	ss.pool.Close()
+	_, err = ss.QueryLatestBlockHeight(context.Background())
+	require.Equal(t, ErrPoolClosed, err)
+}
+
 func TestSporkStoreInit(t *testing.T) {
 	store := NewSporkStore(
 		"mainnet", 5000, 100)
 	require.NotNil(t, store, "store should not be nil")
 
-	err := store.checkReaderHealthy()
+	err := store.checkReaderHealthy(context.Background())
 	require.Nil(t, err, "err should be nil for healthy reader")
 }
 
@@ -35,13 +375,13 @@ func TestE2EFlowTransferEventFetchingCrossSpork(t *testing.T) {
 	testEventEndBlock := 21291000 + 2000
 
 	t.Log("TestE2EFlowTransferEventFetching: fetching events")
-	eventsFromBatch200, err := storeBatch200.QueryEventByBlockRange(testEventSignature, uint64(testEventStartBlock), uint64(testEventEndBlock))
+	eventsFromBatch200, err := storeBatch200.QueryEventByBlockRange(context.Background(), []string{testEventSignature}, uint64(testEventStartBlock), uint64(testEventEndBlock))
 
 	require.Nil(t, err, "err should be nil for storeBatch200 query")
 
 	t.Log("TestE2EFlowTransferEventFetching: fetching events with batch 100 got ", len(eventsFromBatch200))
 
-	eventsFromBatch100, err := storeBatch100.QueryEventByBlockRange(testEventSignature, uint64(testEventStartBlock), uint64(testEventEndBlock))
+	eventsFromBatch100, err := storeBatch100.QueryEventByBlockRange(context.Background(), []string{testEventSignature}, uint64(testEventStartBlock), uint64(testEventEndBlock))
 
 	require.Nil(t, err, "err should be nil for storeBatch100 query")
 
@@ -71,13 +411,13 @@ func TestE2EFlowTransferEventFetchingBatchConsistence(t *testing.T) {
 
 	t.Log("TestE2EFlowTransferEventFetching: fetching events")
 
-	eventsFromBatch1, err := storeBatch1.QueryEventByBlockRange(testEventSignature, uint64(testEventStartBlock), uint64(testEventEndBlock))
+	eventsFromBatch1, err := storeBatch1.QueryEventByBlockRange(context.Background(), []string{testEventSignature}, uint64(testEventStartBlock), uint64(testEventEndBlock))
 
 	require.Nil(t, err, "err should be nil for storeBatch1 query")
 
 	t.Log("TestE2EFlowTransferEventFetching: fetching events with batch  1 got ", len(eventsFromBatch1))
 
-	eventsFromBatch200, err := storeBatch200.QueryEventByBlockRange(testEventSignature, uint64(testEventStartBlock), uint64(testEventEndBlock))
+	eventsFromBatch200, err := storeBatch200.QueryEventByBlockRange(context.Background(), []string{testEventSignature}, uint64(testEventStartBlock), uint64(testEventEndBlock))
 
 	require.Nil(t, err, "err should be nil for storeBatch200 query")
 
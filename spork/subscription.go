@@ -0,0 +1,329 @@
+/**
+ * spork/subscription.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spork
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/client"
+	log "github.com/sirupsen/logrus"
+
+	pb "github.com/MatrixLabsTech/flow-event-fetcher/proto/v1"
+)
+
+var ErrHubClosed = errors.New("subscription hub is closed")
+
+// defaultMaxBlocksPerFetch replaces a zero maxBlocksPerFetch, the widest range access nodes serve at once
+const defaultMaxBlocksPerFetch = 250
+
+// SubscriptionChunk is a batch of events covering blocks [Start, End].
+// A subscriber can resume from Cursor after a disconnect.
+type SubscriptionChunk struct {
+	Start       uint64
+	End         uint64
+	Cursor      uint64
+	BlockEvents []client.BlockEvents
+}
+
+func SubscriptionChunkToJSON(chunk SubscriptionChunk) *pb.StreamEventsByBlockRangeResponse {
+	return &pb.StreamEventsByBlockRangeResponse{
+		Start:  chunk.Start,
+		End:    chunk.End,
+		Cursor: chunk.Cursor,
+		Events: BlockEventsToJSON(chunk.BlockEvents),
+	}
+}
+
+// SubscriptionHub follows the sealed head of the chain and feeds new events to its subscriptions.
+// The head is polled once for all subscriptions, each subscription fetches its own event types.
+type SubscriptionHub struct {
+	sync.Mutex
+
+	flowClient FlowClient
+
+	// pollInterval is the interval between two sealed head queries
+	pollInterval time.Duration
+
+	// stallTimeout is how long the head may stay still before the spork list is synced again
+	stallTimeout time.Duration
+
+	// maxBlocksPerFetch bounds the block range of a single fetch
+	maxBlocksPerFetch uint64
+
+	head uint64
+
+	nextID uint64
+
+	subscriptions map[uint64]*Subscription
+
+	// ctx is cancelled on Close, aborting the queries in flight
+	ctx context.Context
+
+	cancel context.CancelFunc
+
+	done chan struct{}
+}
+
+// NewSubscriptionHub polls the sealed head every pollInterval, a maxBlocksPerFetch of 0 uses defaultMaxBlocksPerFetch
+func NewSubscriptionHub(flowClient FlowClient, pollInterval time.Duration, maxBlocksPerFetch uint64) *SubscriptionHub {
+	if maxBlocksPerFetch == 0 {
+		maxBlocksPerFetch = defaultMaxBlocksPerFetch
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SubscriptionHub{
+		flowClient:        flowClient,
+		pollInterval:      pollInterval,
+		stallTimeout:      5 * time.Minute,
+		maxBlocksPerFetch: maxBlocksPerFetch,
+		subscriptions:     make(map[uint64]*Subscription),
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+	}
+}
+
+// Start polls the sealed head in the background until Close is called
+func (hub *SubscriptionHub) Start() {
+	go hub.run()
+}
+
+func (hub *SubscriptionHub) run() {
+	ticker := time.NewTicker(hub.pollInterval)
+	defer ticker.Stop()
+
+	lastAdvance := time.Now()
+	for {
+		select {
+		case <-hub.done:
+			return
+		case <-ticker.C:
+		}
+
+		hub.Lock()
+		idle := len(hub.subscriptions) == 0
+		hub.Unlock()
+		if idle {
+			lastAdvance = time.Now()
+			continue
+		}
+
+		if hub.poll() {
+			lastAdvance = time.Now()
+			continue
+		}
+
+		// the head has not moved for a while, the access node may belong to a finished spork
+		if time.Since(lastAdvance) > hub.stallTimeout {
+			log.Info("SubscriptionHub: sealed head stalled at ", hub.Head(), ", syncing spork list")
+			if err := hub.flowClient.SyncSpork(); err != nil {
+				log.Error("SubscriptionHub: failed to sync spork ", err)
+			}
+			lastAdvance = time.Now()
+		}
+	}
+}
+
+// poll queries the sealed head and notifies the subscriptions, it reports whether the head advanced.
+// The subscriptions are notified on every tick, one behind the head after a failed fetch retries it even on a stalled chain.
+func (hub *SubscriptionHub) poll() bool {
+	head, err := hub.flowClient.QueryLatestBlockHeight(hub.ctx)
+	if err != nil {
+		log.Error("SubscriptionHub: failed to query latest block height ", err)
+	}
+
+	hub.Lock()
+	defer hub.Unlock()
+	advanced := err == nil && head > hub.head
+	if advanced {
+		hub.head = head
+	}
+	if hub.head > 0 {
+		for _, sub := range hub.subscriptions {
+			sub.notify(hub.head)
+		}
+	}
+	return advanced
+}
+
+// Head returns the last seen sealed height
+func (hub *SubscriptionHub) Head() uint64 {
+	hub.Lock()
+	defer hub.Unlock()
+	return hub.head
+}
+
+// Subscribe follows the given event types from height start, start 0 means the current sealed head
+func (hub *SubscriptionHub) Subscribe(events []string, start uint64) (*Subscription, error) {
+	if len(events) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+
+	hub.Lock()
+	defer hub.Unlock()
+	select {
+	case <-hub.done:
+		return nil, ErrHubClosed
+	default:
+	}
+
+	hub.nextID++
+	ctx, cancel := context.WithCancel(hub.ctx)
+	sub := &Subscription{
+		ID:     hub.nextID,
+		Events: events,
+		C:      make(chan SubscriptionChunk, 16),
+		hub:    hub,
+		ctx:    ctx,
+		cancel: cancel,
+		cursor: start,
+		heads:  make(chan uint64, 1),
+		done:   make(chan struct{}),
+	}
+	hub.subscriptions[sub.ID] = sub
+	if hub.head > 0 {
+		sub.notify(hub.head)
+	}
+	go sub.run()
+
+	log.Info("SubscriptionHub: new subscription ", sub.ID, " for ", events, " from ", start)
+	return sub, nil
+}
+
+func (hub *SubscriptionHub) unsubscribe(sub *Subscription) {
+	hub.Lock()
+	defer hub.Unlock()
+	delete(hub.subscriptions, sub.ID)
+}
+
+// Close stops polling and closes every subscription
+func (hub *SubscriptionHub) Close() error {
+	hub.Lock()
+	select {
+	case <-hub.done:
+		hub.Unlock()
+		return nil
+	default:
+	}
+	close(hub.done)
+	hub.cancel()
+	subscriptions := make([]*Subscription, 0, len(hub.subscriptions))
+	for _, sub := range hub.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	hub.Unlock()
+
+	for _, sub := range subscriptions {
+		sub.Close()
+	}
+	return nil
+}
+
+// Subscription receives the events of its event types on C, in height order.
+// C is closed when the subscription is closed.
+type Subscription struct {
+	ID uint64
+
+	Events []string
+
+	C chan SubscriptionChunk
+
+	hub *SubscriptionHub
+
+	// ctx is cancelled on Close, aborting the query in flight
+	ctx context.Context
+
+	cancel context.CancelFunc
+
+	// cursor is the next height to fetch
+	cursor uint64
+
+	heads chan uint64
+
+	done chan struct{}
+
+	closeOnce sync.Once
+}
+
+// notify hands the latest head to the subscription, replacing a head not yet consumed
+func (sub *Subscription) notify(head uint64) {
+	select {
+	case <-sub.heads:
+	default:
+	}
+	sub.heads <- head
+}
+
+func (sub *Subscription) run() {
+	defer close(sub.C)
+	for {
+		select {
+		case <-sub.done:
+			return
+		case head := <-sub.heads:
+			if sub.cursor == 0 {
+				sub.cursor = head
+			}
+			if !sub.catchUp(head) {
+				return
+			}
+		}
+	}
+}
+
+// catchUp fetches [cursor, head] window by window, it returns false once the subscription is closed
+func (sub *Subscription) catchUp(head uint64) bool {
+	for sub.cursor <= head {
+		end := sub.cursor + sub.hub.maxBlocksPerFetch - 1
+		if end > head {
+			end = head
+		}
+
+		blockEvents, err := sub.fetch(sub.cursor, end)
+		if err != nil {
+			// keep the cursor, the range is fetched again on the next poll
+			log.Error("Subscription: failed to fetch ", sub.cursor, " - ", end, " ", err)
+			return true
+		}
+
+		select {
+		case <-sub.done:
+			return false
+		case sub.C <- SubscriptionChunk{Start: sub.cursor, End: end, Cursor: end + 1, BlockEvents: blockEvents}:
+		}
+		sub.cursor = end + 1
+	}
+	return true
+}
+
+// fetch queries the event types of the subscription, the spork boundaries are resolved by the FlowClient
+func (sub *Subscription) fetch(start uint64, end uint64) ([]client.BlockEvents, error) {
+	return sub.hub.flowClient.QueryEventByBlockRange(sub.ctx, sub.Events, start, end)
+}
+
+// Close stops the subscription
+func (sub *Subscription) Close() {
+	sub.closeOnce.Do(func() {
+		sub.hub.unsubscribe(sub)
+		close(sub.done)
+		sub.cancel()
+		log.Info("SubscriptionHub: subscription ", sub.ID, " closed")
+	})
+}
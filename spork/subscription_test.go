@@ -0,0 +1,264 @@
+package spork
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+	"github.com/stretchr/testify/require"
+)
+
+// headFlowClient is a FlowClient whose chain has one event per block for every event type
+type headFlowClient struct {
+	sync.Mutex
+
+	head uint64
+
+	syncCount int
+}
+
+func (f *headFlowClient) setHead(head uint64) {
+	f.Lock()
+	defer f.Unlock()
+	f.head = head
+}
+
+func (f *headFlowClient) String() string {
+	return "headFlowClient"
+}
+
+func (f *headFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	result := make([]client.BlockEvents, 0)
+	for height := start; height <= end; height++ {
+		events := make([]flow.Event, 0, len(eventTypes))
+		for i, eventType := range eventTypes {
+			events = append(events, flow.Event{Type: eventType, EventIndex: i})
+		}
+		result = append(result, client.BlockEvents{Height: height, Events: events})
+	}
+	return result, nil
+}
+
+func (f *headFlowClient) StreamEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64, handler BlockEventsHandler) error {
+	ret, _ := f.QueryEventByBlockRange(ctx, eventTypes, start, end)
+	return handler(start, end, ret)
+}
+
+func (f *headFlowClient) QueryLatestBlockHeight(ctx context.Context) (uint64, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.head, nil
+}
+
+// QueryBlockHeightByTime treats the block at height h as made at second h
+func (f *headFlowClient) QueryBlockHeightByTime(ctx context.Context, timestamp time.Time) (uint64, error) {
+	height := uint64(0)
+	if timestamp.After(time.Unix(0, 0)) {
+		height = uint64(timestamp.Add(time.Second - 1).Unix())
+	}
+	f.Lock()
+	defer f.Unlock()
+	if height > f.head {
+		height = f.head + 1
+	}
+	return height, nil
+}
+
+func (f *headFlowClient) QueryBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error) {
+	return &flow.BlockHeader{Height: height, Timestamp: time.Unix(int64(height), 0)}, nil
+}
+
+func (f *headFlowClient) QueryTransaction(ctx context.Context, height uint64, txID flow.Identifier) (*TransactionInfo, error) {
+	return &TransactionInfo{Status: flow.TransactionStatusSealed}, nil
+}
+
+func (f *headFlowClient) SyncSpork() error {
+	f.Lock()
+	defer f.Unlock()
+	f.syncCount++
+	return nil
+}
+
+func (f *headFlowClient) Close() error {
+	return nil
+}
+
+func receiveChunk(t *testing.T, sub *Subscription) SubscriptionChunk {
+	select {
+	case chunk, ok := <-sub.C:
+		require.True(t, ok, "subscription should be open")
+		return chunk
+	case <-time.After(time.Second):
+		t.Fatal("no chunk received")
+	}
+	return SubscriptionChunk{}
+}
+
+func TestSubscriptionFollowsHead(t *testing.T) {
+	flowClient := &headFlowClient{head: 100}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 10)
+	hub.Start()
+	defer hub.Close()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar", "A.0x1.Foo.Baz"}, 0)
+	require.Nil(t, err)
+
+	chunk := receiveChunk(t, sub)
+	require.Equal(t, uint64(100), chunk.Start)
+	require.Equal(t, uint64(100), chunk.End)
+	require.Equal(t, uint64(101), chunk.Cursor)
+	require.Len(t, chunk.BlockEvents, 1)
+	require.Len(t, chunk.BlockEvents[0].Events, 2)
+
+	flowClient.setHead(115)
+	chunk = receiveChunk(t, sub)
+	require.Equal(t, uint64(101), chunk.Start)
+	require.Equal(t, uint64(110), chunk.End)
+	require.Len(t, chunk.BlockEvents, 10)
+
+	chunk = receiveChunk(t, sub)
+	require.Equal(t, uint64(111), chunk.Start)
+	require.Equal(t, uint64(115), chunk.End)
+}
+
+func TestSubscriptionResumeFromCursor(t *testing.T) {
+	flowClient := &headFlowClient{head: 100}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 50)
+	hub.Start()
+	defer hub.Close()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 90)
+	require.Nil(t, err)
+
+	chunk := receiveChunk(t, sub)
+	require.Equal(t, uint64(90), chunk.Start)
+	require.Equal(t, uint64(100), chunk.End)
+	require.Len(t, chunk.BlockEvents, 11)
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	flowClient := &headFlowClient{head: 100}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 10)
+	hub.Start()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 0)
+	require.Nil(t, err)
+	receiveChunk(t, sub)
+
+	hub.Close()
+	select {
+	case _, ok := <-sub.C:
+		require.False(t, ok, "subscription should be closed")
+	case <-time.After(time.Second):
+		t.Fatal("subscription not closed")
+	}
+
+	_, err = hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 0)
+	require.Equal(t, ErrHubClosed, err)
+}
+
+func TestSubscriptionSyncSporkOnStall(t *testing.T) {
+	flowClient := &headFlowClient{head: 100}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 10)
+	hub.stallTimeout = 20 * time.Millisecond
+	hub.Start()
+	defer hub.Close()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 0)
+	require.Nil(t, err)
+	receiveChunk(t, sub)
+
+	require.Eventually(t, func() bool {
+		flowClient.Lock()
+		defer flowClient.Unlock()
+		return flowClient.syncCount > 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSubscriptionDefaultMaxBlocksPerFetch(t *testing.T) {
+	flowClient := &headFlowClient{head: 1000}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 0)
+	hub.Start()
+	defer hub.Close()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 500)
+	require.Nil(t, err)
+
+	chunk := receiveChunk(t, sub)
+	require.Equal(t, uint64(500), chunk.Start)
+	require.Equal(t, uint64(500+defaultMaxBlocksPerFetch-1), chunk.End)
+}
+
+// blockingFlowClient holds every event query until its context is done
+type blockingFlowClient struct {
+	headFlowClient
+
+	started chan struct{}
+
+	cancelled chan error
+}
+
+func (f *blockingFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	f.started <- struct{}{}
+	<-ctx.Done()
+	f.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestSubscriptionCloseCancelsFetch(t *testing.T) {
+	flowClient := &blockingFlowClient{headFlowClient: headFlowClient{head: 100}, started: make(chan struct{}, 1), cancelled: make(chan error, 1)}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 10)
+	hub.Start()
+	defer hub.Close()
+
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 0)
+	require.Nil(t, err)
+	<-flowClient.started
+
+	// closing one subscription aborts its query while the hub keeps running
+	sub.Close()
+	select {
+	case err := <-flowClient.cancelled:
+		require.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Fatal("query of the closed subscription not cancelled")
+	}
+	require.Nil(t, hub.ctx.Err())
+}
+
+// flakyFlowClient fails the first failures event queries
+type flakyFlowClient struct {
+	headFlowClient
+
+	failures int
+}
+
+func (f *flakyFlowClient) QueryEventByBlockRange(ctx context.Context, eventTypes []string, start uint64, end uint64) ([]client.BlockEvents, error) {
+	f.Lock()
+	failing := f.failures > 0
+	f.failures--
+	f.Unlock()
+	if failing {
+		return nil, errors.New("node down")
+	}
+	return f.headFlowClient.QueryEventByBlockRange(ctx, eventTypes, start, end)
+}
+
+func TestSubscriptionRetriesFailedFetchOnStalledHead(t *testing.T) {
+	flowClient := &flakyFlowClient{headFlowClient: headFlowClient{head: 100}, failures: 2}
+	hub := NewSubscriptionHub(flowClient, 5*time.Millisecond, 10)
+	hub.Start()
+	defer hub.Close()
+
+	// the head never moves, the failed range is fetched again on the next ticks
+	sub, err := hub.Subscribe([]string{"A.0x1.Foo.Bar"}, 95)
+	require.Nil(t, err)
+	chunk := receiveChunk(t, sub)
+	require.Equal(t, uint64(95), chunk.Start)
+	require.Equal(t, uint64(100), chunk.End)
+	require.Len(t, chunk.BlockEvents, 6)
+}
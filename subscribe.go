@@ -0,0 +1,187 @@
+/**
+ * subscribe.go
+ * Copyright (c) 2022 Alvin(Xinyao) Sun <asun@matrixworld.org>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MatrixLabsTech/flow-event-fetcher/server"
+	"github.com/MatrixLabsTech/flow-event-fetcher/spork"
+)
+
+var subscriptionHub *spork.SubscriptionHub
+
+// allowedOrigins are the origins allowed to open a WebSocket subscription besides the own one, "*" allows any
+var allowedOrigins []string
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: checkOrigin,
+}
+
+// checkOrigin accepts requests without an Origin header, from the same host or from allowedOrigins,
+// so the page of another site cannot open a subscription in the name of its visitors
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOrigins splits the comma separated origins of the subscribeOrigins flag
+func parseOrigins(origins string) []string {
+	result := make([]string, 0)
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			result = append(result, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return result
+}
+
+type SubscribeEventsDto struct {
+	Events []string `form:"event" binding:"required"`
+	Start  uint64   `form:"start"`
+}
+
+func subscribe(c *gin.Context) (*spork.Subscription, bool) {
+	var subscribeEventsDto SubscribeEventsDto
+	err := c.ShouldBindQuery(&subscribeEventsDto)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return nil, false
+	}
+	for _, eventType := range subscribeEventsDto.Events {
+		if err := server.ValidateEventType(eventType); err != nil {
+			c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+			return nil, false
+		}
+	}
+
+	sub, err := subscriptionHub.Subscribe(subscribeEventsDto.Events, subscribeEventsDto.Start)
+	if err != nil {
+		log.Error(err.Error())
+		c.JSON(http.StatusBadRequest, ResponseError{Error: err.Error()})
+		return nil, false
+	}
+	return sub, true
+}
+
+// subscribeSSE subscribe events with server-sent events
+// @Summary subscribes events with server-sent events
+// @Description follows the sealed head and sends an "events" message per fetched batch.
+// @Description Pass start to resume from the cursor of the last received batch.
+// @Tags flow-event-fetcher
+// @Produce text/event-stream
+// @Param event query []string true "event types" collectionFormat(multi)
+// @Param start query int false "start height, defaults to the current sealed head"
+// @Success 200 {object} v1.StreamEventsByBlockRangeResponse
+// @Failure 400 {object} ResponseError
+// @Router /subscribe/sse [get]
+func subscribeSSE(c *gin.Context) {
+	sub, ok := subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case chunk, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			c.SSEvent("events", spork.SubscriptionChunkToJSON(chunk))
+			return true
+		}
+	})
+}
+
+// subscribeWebSocket subscribe events with websocket
+// @Summary subscribes events with websocket
+// @Description follows the sealed head and sends a JSON message per fetched batch.
+// @Description Pass start to resume from the cursor of the last received batch.
+// @Tags flow-event-fetcher
+// @Param event query []string true "event types" collectionFormat(multi)
+// @Param start query int false "start height, defaults to the current sealed head"
+// @Success 101 {object} v1.StreamEventsByBlockRangeResponse
+// @Failure 400 {object} ResponseError
+// @Router /subscribe/ws [get]
+func subscribeWebSocket(c *gin.Context) {
+	sub, ok := subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	defer conn.Close()
+
+	// drain the incoming messages so close frames are processed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case chunk, ok := <-sub.C:
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "subscription closed"))
+				return
+			}
+			if err := conn.WriteJSON(spork.SubscriptionChunkToJSON(chunk)); err != nil {
+				log.Error(err.Error())
+				return
+			}
+		}
+	}
+}